@@ -0,0 +1,70 @@
+package discoverygo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidApiKey is returned by NewDiscoveryClient for an API key that
+// can't be valid, e.g. one with whitespace from a copy and paste. An
+// *APIError for a key the API rejects also matches it with errors.Is
+var ErrInvalidApiKey = errors.New("Invalid API key")
+
+// apiKeyLength is the length of the consumer keys the API currently issues
+const apiKeyLength = 32
+
+// ValidateAPIKey returns ErrMissingApiKey if the key is empty (or only
+// whitespace), or an error wrapping ErrInvalidApiKey if it contains
+// whitespace or control characters. It's deliberately lenient, so keys
+// aren't rejected if their format changes
+func ValidateAPIKey(key string) error {
+	if strings.TrimSpace(key) == "" {
+		return ErrMissingApiKey
+	}
+	for i, r := range key {
+		if unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return fmt.Errorf("%w: unexpected %q at %d", ErrInvalidApiKey, r, i)
+		}
+	}
+	return nil
+}
+
+// looksLikeAPIKey reports whether the key has the length and characters of
+// the consumer keys the API currently issues
+func looksLikeAPIKey(key string) bool {
+	if len(key) != apiKeyLength {
+		return false
+	}
+	for _, r := range key {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// apiKeyContextKey is the context key for an API key set with
+// ContextWithAPIKey
+type apiKeyContextKey struct{}
+
+// ContextWithAPIKey returns a copy of ctx carrying the given API key, which
+// requests made with the context send in place of the client's ApiKey
+// (even if DisableAPIKey is set). It lets one client be shared by callers
+// with their own keys, e.g. the tenants of a service. Cached responses and
+// ETags are kept separately for each key
+func ContextWithAPIKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey{}, key)
+}
+
+// requestAPIKey returns the API key to send with a request made with the
+// given context: the key from ContextWithAPIKey if there is one, or else
+// the client's own
+func (d *DiscoveryClient) requestAPIKey(ctx context.Context) string {
+	if key, ok := ctx.Value(apiKeyContextKey{}).(string); ok && key != "" {
+		return key
+	}
+	return d.apiKey()
+}
@@ -0,0 +1,85 @@
+package discoverygo
+
+import (
+	"context"
+	"net/url"
+)
+
+// GetAttraction returns an attraction by its ID
+// See: https://developer.ticketmaster.com/products-and-docs/apis/discovery-api/v2/#attraction-details-v2
+func (d *DiscoveryClient) GetAttraction(id string) (*Attraction, error) {
+	return d.GetAttractionContext(context.Background(), id)
+}
+
+// GetAttractionContext returns an attraction by its ID. The request is
+// bound to the given context
+func (d *DiscoveryClient) GetAttractionContext(
+	ctx context.Context,
+	id string,
+) (*Attraction, error) {
+	var rs Attraction
+	if err := d.doRequest(ctx, d.attractionUrl(id), &rs); err != nil {
+		return nil, err
+	}
+	return &rs, nil
+}
+
+// SearchAttractions returns a list of attractions matching the given
+// query parameters
+// See: https://developer.ticketmaster.com/products-and-docs/apis/discovery-api/v2/#search-attractions-v2
+func (d *DiscoveryClient) SearchAttractions(
+	queryParams QueryParams,
+) (*PagedResponse, error) {
+	return d.SearchAttractionsContext(context.Background(), queryParams)
+}
+
+// SearchAttractionsContext returns a list of attractions matching the
+// given query parameters. The request is bound to the given context
+func (d *DiscoveryClient) SearchAttractionsContext(
+	ctx context.Context,
+	queryParams QueryParams,
+) (*PagedResponse, error) {
+	attractionsUrl, err := d.searchURL(d.AttractionsUrl(), queryParams)
+	if err != nil {
+		return nil, err
+	}
+	var rs PagedResponse
+	if err := d.doRequest(ctx, *attractionsUrl, &rs); err != nil {
+		return nil, err
+	}
+	rs.params = &queryParams
+	return &rs, nil
+}
+
+// EventsForAttraction returns the upcoming events of the attraction with
+// the given ID, e.g. an artist's tour dates. The ID overrides
+// queryParams.AttractionID. Unless set in queryParams, events are sorted by
+// date and start from now
+func (d *DiscoveryClient) EventsForAttraction(
+	attractionID string,
+	queryParams QueryParams,
+) (*PagedResponse, error) {
+	return d.EventsForAttractionContext(
+		context.Background(),
+		attractionID,
+		queryParams,
+	)
+}
+
+// EventsForAttractionContext returns the upcoming events of the attraction
+// with the given ID. The request is bound to the given context
+func (d *DiscoveryClient) EventsForAttractionContext(
+	ctx context.Context,
+	attractionID string,
+	queryParams QueryParams,
+) (*PagedResponse, error) {
+	queryParams = queryParams.upcoming()
+	queryParams.AttractionID = []string{attractionID}
+	return d.SearchEventsContext(ctx, queryParams)
+}
+
+// attractionUrl returns the URL to the attraction with the given ID
+func (d *DiscoveryClient) attractionUrl(id string) url.URL {
+	baseAttractionUrl := d.AttractionsUrl()
+	return *baseAttractionUrl.JoinPath(id)
+}
@@ -0,0 +1,55 @@
+package discoverygo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DefaultBatchConcurrency is the number of events GetEvents requests at
+// once when the client's Concurrency isn't set
+const DefaultBatchConcurrency = 4
+
+// GetEvents returns the events with the given IDs, requesting up to the
+// client's Concurrency (or DefaultBatchConcurrency) at once. Events are
+// returned in the same order as ids. If any requests fail, the result for
+// that ID is nil, and the events that were fetched are returned along with
+// an error joining each failure
+func (d *DiscoveryClient) GetEvents(
+	ctx context.Context,
+	ids []string,
+) ([]*Event, error) {
+	workers := d.Concurrency
+	if workers < 1 {
+		workers = DefaultBatchConcurrency
+	}
+	if workers > len(ids) {
+		workers = len(ids)
+	}
+
+	events := make([]*Event, len(ids))
+	errs := make([]error, len(ids))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				event, err := d.GetEventTypedContext(ctx, ids[i])
+				if err != nil {
+					errs[i] = fmt.Errorf("event %s: %w", ids[i], err)
+					continue
+				}
+				events[i] = event
+			}
+		}()
+	}
+	for i := range ids {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return events, errors.Join(errs...)
+}
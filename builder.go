@@ -0,0 +1,160 @@
+package discoverygo
+
+import (
+	"strconv"
+	"time"
+)
+
+// QueryParamsBuilder builds QueryParams fluently, for the common case of a
+// handful of parameters:
+//
+//	params, err := discoverygo.NewQueryParamsBuilder().
+//		Keyword("radiohead").
+//		CountryCode(discoverygo.CountryUS).
+//		Size(50).
+//		Build()
+//
+// The parameters are validated by Build
+type QueryParamsBuilder struct {
+	params QueryParams
+}
+
+// NewQueryParamsBuilder returns a QueryParamsBuilder with no parameters set
+func NewQueryParamsBuilder() *QueryParamsBuilder {
+	return &QueryParamsBuilder{}
+}
+
+// Keyword sets the keyword to search for
+func (b *QueryParamsBuilder) Keyword(keyword string) *QueryParamsBuilder {
+	b.params.Keyword = keyword
+	return b
+}
+
+// Page sets the page number, starting from zero
+func (b *QueryParamsBuilder) Page(page int) *QueryParamsBuilder {
+	b.params.Page = page
+	return b
+}
+
+// Size sets the number of results per page
+func (b *QueryParamsBuilder) Size(size int) *QueryParamsBuilder {
+	b.params.Size = size
+	return b
+}
+
+// Sort sets the sort order, e.g. SortDateAsc
+func (b *QueryParamsBuilder) Sort(sort string) *QueryParamsBuilder {
+	b.params.Sort = sort
+	return b
+}
+
+// Locale sets the locale, e.g. "en-us"
+func (b *QueryParamsBuilder) Locale(locale string) *QueryParamsBuilder {
+	b.params.Locale = locale
+	return b
+}
+
+// CountryCode sets the country code, e.g. CountryUS
+func (b *QueryParamsBuilder) CountryCode(code string) *QueryParamsBuilder {
+	b.params.CountryCode = code
+	return b
+}
+
+// StateCode sets the state code, e.g. "NY"
+func (b *QueryParamsBuilder) StateCode(code string) *QueryParamsBuilder {
+	b.params.StateCode = code
+	return b
+}
+
+// PostalCode sets the postal (ZIP) code to filter by, e.g. "10001"
+func (b *QueryParamsBuilder) PostalCode(code string) *QueryParamsBuilder {
+	b.params.PostalCode = code
+	return b
+}
+
+// DateRange sets the start and end of the date range. A zero time leaves
+// that end of the range unset
+func (b *QueryParamsBuilder) DateRange(start, end time.Time) *QueryParamsBuilder {
+	b.params.SetDateRange(start, end)
+	return b
+}
+
+// VenueID adds venue IDs to filter by
+func (b *QueryParamsBuilder) VenueID(ids ...string) *QueryParamsBuilder {
+	b.params.VenueID = append(b.params.VenueID, ids...)
+	return b
+}
+
+// AttractionID adds attraction IDs to filter by
+func (b *QueryParamsBuilder) AttractionID(ids ...string) *QueryParamsBuilder {
+	b.params.AttractionID = append(b.params.AttractionID, ids...)
+	return b
+}
+
+// ClassificationName sets the classification name to filter by, e.g. "rock"
+func (b *QueryParamsBuilder) ClassificationName(
+	name string,
+) *QueryParamsBuilder {
+	b.params.ClassificationName = name
+	return b
+}
+
+// SegmentName sets the segment name to filter by, e.g. "Music"
+func (b *QueryParamsBuilder) SegmentName(name string) *QueryParamsBuilder {
+	b.params.SegmentName = name
+	return b
+}
+
+// MarketID adds market IDs to filter by
+func (b *QueryParamsBuilder) MarketID(ids ...string) *QueryParamsBuilder {
+	b.params.MarketID = append(b.params.MarketID, ids...)
+	return b
+}
+
+// DmaID sets the DMA ID to filter by, e.g. DmaNewYork
+func (b *QueryParamsBuilder) DmaID(id int) *QueryParamsBuilder {
+	b.params.DmaID = strconv.Itoa(id)
+	return b
+}
+
+// GeoPoint sets the point to search around, as a geohash of the given
+// coordinates
+func (b *QueryParamsBuilder) GeoPoint(lat, long float64) *QueryParamsBuilder {
+	b.params.GeoPoint = Geohash(lat, long, 9)
+	return b
+}
+
+// Radius sets the distance to search around GeoPoint, LatLong or
+// PostalCode, in the given unit (UnitMiles or UnitKm). See SetRadius
+func (b *QueryParamsBuilder) Radius(
+	radius int,
+	unit string,
+) *QueryParamsBuilder {
+	b.params.SetRadius(radius, unit)
+	return b
+}
+
+// Source sets the source to filter by, e.g. "ticketmaster"
+func (b *QueryParamsBuilder) Source(source string) *QueryParamsBuilder {
+	b.params.Source = source
+	return b
+}
+
+// IncludeTest sets whether test events are included: IncludeYes, IncludeNo
+// or IncludeOnly
+func (b *QueryParamsBuilder) IncludeTest(
+	include IncludeFilter,
+) *QueryParamsBuilder {
+	b.params.IncludeTest = include
+	return b
+}
+
+// Build returns the QueryParams, or an error if they're invalid
+func (b *QueryParamsBuilder) Build() (QueryParams, error) {
+	// Copy slices so changes to the builder don't affect built params
+	params := b.params.Clone()
+	if err := params.Validate(); err != nil {
+		return QueryParams{}, err
+	}
+	return params, nil
+}
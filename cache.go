@@ -0,0 +1,227 @@
+package discoverygo
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is how long responses are cached when neither the
+// response nor the client's CacheTTL specify otherwise
+const DefaultCacheTTL = time.Minute
+
+// Cache stores response bodies for a DiscoveryClient, keyed by the
+// request URL (with the API key redacted) and a hash of the API key, so
+// callers with their own keys (see ContextWithAPIKey) don't share entries
+type Cache interface {
+	// Get returns the value stored for key, if it exists and hasn't expired
+	Get(key string) ([]byte, bool)
+	// Set stores value for key, for the given duration
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// cacheSweepInterval is how often MemoryCache.Set removes expired entries
+const cacheSweepInterval = time.Minute
+
+// MemoryCache is an in-memory Cache. It's safe for concurrent use.
+// Expired entries are removed when they're looked up, and swept at most
+// once a minute when a value is stored, or whenever the cache is full
+type MemoryCache struct {
+	// Maximum number of entries. If positive, storing a new entry in a
+	// full cache evicts the one closest to expiring. Set it before the
+	// cache is used
+	MaxEntries int
+
+	mu        sync.Mutex
+	entries   map[string]cacheEntry
+	nextSweep time.Time
+}
+
+type cacheEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache returns an empty MemoryCache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: map[string]cacheEntry{}}
+}
+
+// Get returns the value stored for key, if it exists and hasn't expired
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// Set stores value for key, for the given duration
+func (c *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	_, exists := c.entries[key]
+	full := c.MaxEntries > 0 && !exists && len(c.entries) >= c.MaxEntries
+	if full || now.After(c.nextSweep) {
+		c.sweep(now)
+		c.nextSweep = now.Add(cacheSweepInterval)
+	}
+	if c.MaxEntries > 0 && !exists && len(c.entries) >= c.MaxEntries {
+		c.evict()
+	}
+	c.entries[key] = cacheEntry{value: value, expires: now.Add(ttl)}
+}
+
+// sweep removes the entries that have expired by now
+func (c *MemoryCache) sweep(now time.Time) {
+	for key, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// evict removes the entry closest to expiring
+func (c *MemoryCache) evict() {
+	var oldest string
+	var expires time.Time
+	for key, entry := range c.entries {
+		if expires.IsZero() || entry.expires.Before(expires) {
+			oldest, expires = key, entry.expires
+		}
+	}
+	delete(c.entries, oldest)
+}
+
+// Len returns the number of entries in the cache, including any that have
+// expired but haven't been removed yet
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// cachedResponse returns a response built from the cached body for the
+// given request, if there is one
+func (d *DiscoveryClient) cachedResponse(
+	req *http.Request,
+) (*http.Response, bool) {
+	if d.Cache == nil || uncached(req) {
+		return nil, false
+	}
+	body, ok := d.Cache.Get(d.cacheKey(req))
+	if !ok {
+		return nil, false
+	}
+	d.logger().Debug("Cache hit", "url", RedactURL(*req.URL))
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, true
+}
+
+// cacheResponse stores the body of a successful response in the client's
+// cache, unless the response's Cache-Control header forbids it or the body
+// isn't JSON (so a gateway's error page is never replayed as the API's
+// response). The body is read in full and replaced, so the response can
+// still be decoded
+func (d *DiscoveryClient) cacheResponse(
+	req *http.Request,
+	resp *http.Response,
+) (*http.Response, error) {
+	if d.Cache == nil || uncached(req) || resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	ttl := cacheTTL(resp.Header, d.CacheTTL)
+	if ttl <= 0 {
+		return resp, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if !isJSONBody(resp.Header.Get("Content-Type"), body) {
+		return resp, nil
+	}
+	d.Cache.Set(d.cacheKey(req), body, ttl)
+	return resp, nil
+}
+
+// cacheKey returns the key the response to the given request is cached
+// (and its ETag stored) under: the redacted URL, followed by a hash of the
+// API key the request is sent with, if there is one
+func (d *DiscoveryClient) cacheKey(req *http.Request) string {
+	key := RedactURL(*req.URL)
+	apiKey := d.requestAPIKey(req.Context())
+	if apiKey == "" {
+		return key
+	}
+	sum := sha256.Sum256([]byte(apiKey))
+	return key + "#" + hex.EncodeToString(sum[:8])
+}
+
+// uncachedContextKey is the context key marking a request that must reach
+// the API, so it bypasses the client's Cache and ETags
+type uncachedContextKey struct{}
+
+// uncached reports whether the given request bypasses the client's Cache
+// and ETags
+func uncached(req *http.Request) bool {
+	bypass, _ := req.Context().Value(uncachedContextKey{}).(bool)
+	return bypass
+}
+
+// cacheTTL returns how long a response may be cached, according to its
+// Cache-Control or Expires headers. If neither is set, fallback is used,
+// or DefaultCacheTTL if fallback is zero
+func cacheTTL(header http.Header, fallback time.Duration) time.Duration {
+	if cacheControl := header.Get("Cache-Control"); cacheControl != "" {
+		for _, directive := range strings.Split(cacheControl, ",") {
+			directive = strings.ToLower(strings.TrimSpace(directive))
+			switch {
+			case directive == "no-store", directive == "no-cache":
+				return 0
+			case strings.HasPrefix(directive, "max-age="):
+				seconds, err := strconv.Atoi(
+					strings.TrimPrefix(directive, "max-age="),
+				)
+				if err == nil {
+					return time.Duration(seconds) * time.Second
+				}
+			}
+		}
+	}
+	if expires := header.Get("Expires"); expires != "" {
+		if date, err := http.ParseTime(expires); err == nil {
+			return time.Until(date)
+		}
+		// Invalid dates, such as "0", mean already expired
+		return 0
+	}
+	if fallback == 0 {
+		return DefaultCacheTTL
+	}
+	return fallback
+}
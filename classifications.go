@@ -0,0 +1,64 @@
+package discoverygo
+
+import (
+	"context"
+	"net/url"
+)
+
+// GetClassification returns a classification by its ID. The ID may be
+// that of a segment, genre or sub-genre
+// See: https://developer.ticketmaster.com/products-and-docs/apis/discovery-api/v2/#classifications-details-v2
+func (d *DiscoveryClient) GetClassification(
+	id string,
+) (*Classification, error) {
+	return d.GetClassificationContext(context.Background(), id)
+}
+
+// GetClassificationContext returns a classification by its ID. The request
+// is bound to the given context
+func (d *DiscoveryClient) GetClassificationContext(
+	ctx context.Context,
+	id string,
+) (*Classification, error) {
+	var rs Classification
+	if err := d.doRequest(ctx, d.classificationUrl(id), &rs); err != nil {
+		return nil, err
+	}
+	return &rs, nil
+}
+
+// SearchClassifications returns a list of classifications matching the
+// given query parameters
+// See: https://developer.ticketmaster.com/products-and-docs/apis/discovery-api/v2/#search-classifications-v2
+func (d *DiscoveryClient) SearchClassifications(
+	queryParams QueryParams,
+) (*PagedResponse, error) {
+	return d.SearchClassificationsContext(context.Background(), queryParams)
+}
+
+// SearchClassificationsContext returns a list of classifications matching
+// the given query parameters. The request is bound to the given context
+func (d *DiscoveryClient) SearchClassificationsContext(
+	ctx context.Context,
+	queryParams QueryParams,
+) (*PagedResponse, error) {
+	classificationsUrl, err := d.searchURL(
+		d.ClassificationsUrl(),
+		queryParams,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var rs PagedResponse
+	if err := d.doRequest(ctx, *classificationsUrl, &rs); err != nil {
+		return nil, err
+	}
+	rs.params = &queryParams
+	return &rs, nil
+}
+
+// classificationUrl returns the URL to the classification with the given ID
+func (d *DiscoveryClient) classificationUrl(id string) url.URL {
+	baseClassificationUrl := d.ClassificationsUrl()
+	return *baseClassificationUrl.JoinPath(id)
+}
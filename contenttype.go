@@ -0,0 +1,70 @@
+package discoverygo
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ErrUnexpectedContentType is returned when a successful response isn't
+// JSON, e.g. an HTML error page from a gateway in front of the API
+var ErrUnexpectedContentType = errors.New("Unexpected content type")
+
+// contentSnippetLength is how much of an unexpected response body is
+// included in the error
+const contentSnippetLength = 64
+
+// isJSONContentType reports whether the given media type is JSON, e.g.
+// application/json or application/hal+json
+func isJSONContentType(mediaType string) bool {
+	return mediaType == "application/json" ||
+		strings.HasSuffix(mediaType, "+json")
+}
+
+// jsonBody returns the body of the given response to be decoded, or an
+// error wrapping ErrUnexpectedContentType with the start of the body if it
+// isn't JSON. A body labelled with another content type is still accepted
+// if it starts like a JSON object or array, since some servers and proxies
+// mislabel JSON as text/plain
+func jsonBody(resp *http.Response) (io.Reader, error) {
+	contentType := resp.Header.Get("Content-Type")
+	if acceptsJSON(contentType) {
+		return resp.Body, nil
+	}
+	body := bufio.NewReader(resp.Body)
+	peeked, _ := body.Peek(contentSnippetLength)
+	if startsLikeJSON(peeked) {
+		return body, nil
+	}
+	return nil, fmt.Errorf(
+		"%w: expected JSON, got %s: %s",
+		ErrUnexpectedContentType,
+		contentType,
+		bytes.TrimSpace(peeked),
+	)
+}
+
+// acceptsJSON reports whether a body with the given content type is
+// decoded as JSON without looking at it: it's JSON, or there is none
+func acceptsJSON(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return contentType == "" || isJSONContentType(mediaType)
+}
+
+// startsLikeJSON reports whether the given start of a body looks like a
+// JSON object or array
+func startsLikeJSON(start []byte) bool {
+	trimmed := bytes.TrimSpace(start)
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')
+}
+
+// isJSONBody reports whether jsonBody accepts a response with the given
+// content type and body
+func isJSONBody(contentType string, body []byte) bool {
+	return acceptsJSON(contentType) || startsLikeJSON(body)
+}
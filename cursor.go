@@ -0,0 +1,79 @@
+package discoverygo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// ErrInvalidCursor is returned by ResumeFrom for a cursor that can't be
+// parsed, e.g. one that's empty or wasn't returned by Cursor
+var ErrInvalidCursor = errors.New("Invalid cursor")
+
+// Cursor returns a cursor for resuming the search this response came from
+// after this page, e.g. after a long crawl is interrupted. It's the
+// search's query parameters for the next page (with this page's size),
+// encoded as by QueryParams.Encode, so it can be stored as a string. It returns false if
+// there's no next page, or the response didn't come from an event search,
+// since ResumeFrom only resumes those
+func (p *PagedResponse) Cursor() (string, bool) {
+	params, ok := p.Params()
+	if !ok || !p.eventSearch || !p.HasNext() {
+		return "", false
+	}
+	params = params.WithPage(p.Page.Number + 1)
+	if params.Size == 0 {
+		params.Size = p.Page.Size
+	}
+	return params.Encode(), true
+}
+
+// Cursor returns a cursor for resuming iteration after the current page.
+// See PagedResponse.Cursor. It returns false before Next is called, and
+// once there are no more pages
+func (it *PageIterator) Cursor() (string, bool) {
+	if it.page == nil {
+		return "", false
+	}
+	return it.page.Cursor()
+}
+
+// ResumeFrom returns a PageIterator over the rest of an event search, from
+// the page of a cursor from PagedResponse.Cursor or PageIterator.Cursor
+func (d *DiscoveryClient) ResumeFrom(cursor string) (*PageIterator, error) {
+	return d.ResumeFromContext(context.Background(), cursor)
+}
+
+// ResumeFromContext returns a PageIterator over the rest of an event
+// search, from the page of the given cursor. Each page is requested with
+// the given context. A cursor without a page and size, or with invalid
+// query parameters, returns an error wrapping ErrInvalidCursor, rather
+// than starting the search over
+func (d *DiscoveryClient) ResumeFromContext(
+	ctx context.Context,
+	cursor string,
+) (*PageIterator, error) {
+	values, err := url.ParseQuery(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if !values.Has("page") || !values.Has("size") {
+		return nil, fmt.Errorf("%w: no page and size", ErrInvalidCursor)
+	}
+	queryParams, err := ParseQueryParams(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if queryParams.Page < 1 || queryParams.Size < 1 {
+		return nil, fmt.Errorf(
+			"%w: invalid page or size: %s",
+			ErrInvalidCursor,
+			cursor,
+		)
+	}
+	if err := queryParams.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return d.EventsIteratorContext(ctx, queryParams), nil
+}
@@ -0,0 +1,118 @@
+package discoverygo
+
+import (
+	"fmt"
+	"time"
+)
+
+// DateTimeLayout is the format the Discovery API expects for the
+// startDateTime and endDateTime query parameters
+const DateTimeLayout = "2006-01-02T15:04:05Z"
+
+// FormatDateTime formats t for the startDateTime and endDateTime query
+// parameters: converted to UTC, to the second
+func FormatDateTime(t time.Time) string {
+	return t.UTC().Format(DateTimeLayout)
+}
+
+// ParseDateTime parses a date-time string returned by the Discovery API,
+// such as an event's dates.start.dateTime
+func ParseDateTime(s string) (time.Time, error) {
+	return time.Parse(time.RFC3339, s)
+}
+
+// localDateTimeLayout is the layout of an event's start localDate and
+// localTime, joined by a space
+const localDateTimeLayout = "2006-01-02 15:04:05"
+
+// StartTime returns when the event starts, in its timezone if it can be
+// loaded. DateTime is preferred, falling back to LocalDate and LocalTime in
+// the event's timezone. It returns false if the date or time is TBA or TBD,
+// the event has no specific time, or the start can't be determined
+func (d Dates) StartTime() (time.Time, bool) {
+	start := d.Start
+	if start.DateTBA || start.DateTBD || start.TimeTBA || start.NoSpecificTime {
+		return time.Time{}, false
+	}
+	var loc *time.Location
+	if d.Timezone != "" {
+		loc, _ = time.LoadLocation(d.Timezone)
+	}
+	if start.DateTime != "" {
+		if t, err := ParseDateTime(start.DateTime); err == nil {
+			if loc != nil {
+				t = t.In(loc)
+			}
+			return t, true
+		}
+	}
+	if loc == nil || start.LocalDate == "" || start.LocalTime == "" {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(
+		localDateTimeLayout,
+		start.LocalDate+" "+start.LocalTime,
+		loc,
+	)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// SetDateRange sets StartDateTime and EndDateTime to the given times. A
+// zero time leaves that end of the range unset
+func (q *QueryParams) SetDateRange(start, end time.Time) {
+	q.StartDateTime = ""
+	q.EndDateTime = ""
+	if !start.IsZero() {
+		q.StartDateTime = FormatDateTime(start)
+	}
+	if !end.IsZero() {
+		q.EndDateTime = FormatDateTime(end)
+	}
+}
+
+// FutureOnly returns a copy of the query parameters (see Clone) limited to
+// events that haven't started yet, by setting StartDateTime to the current
+// time in UTC. An explicit StartDateTime is kept, since it's only set when
+// empty. Call it when searching, e.g. SearchEvents(params.FutureOnly()),
+// so the time is current
+func (q QueryParams) FutureOnly() QueryParams {
+	q = q.Clone()
+	if q.StartDateTime == "" {
+		q.StartDateTime = FormatDateTime(time.Now())
+	}
+	return q
+}
+
+// upcoming returns a copy of the query parameters defaulting to upcoming
+// events in date order: Sort is SortDateAsc, unless it's already set, and
+// StartDateTime is set as by FutureOnly
+func (q QueryParams) upcoming() QueryParams {
+	q = q.FutureOnly()
+	if q.Sort == "" {
+		q.Sort = SortDateAsc
+	}
+	return q
+}
+
+// validateDateRange checks that the range between StartDateTime and
+// EndDateTime isn't reversed. Values in other formats than DateTimeLayout
+// (or another RFC 3339 date-time) are left for the API to check, so the
+// range is only checked if both parse
+func (q QueryParams) validateDateRange() error {
+	start, startErr := time.Parse(time.RFC3339, q.StartDateTime)
+	end, endErr := time.Parse(time.RFC3339, q.EndDateTime)
+	if startErr != nil || endErr != nil {
+		return nil
+	}
+	if end.Before(start) {
+		return fmt.Errorf(
+			"Invalid date range: endDateTime %s is before startDateTime %s",
+			q.EndDateTime,
+			q.StartDateTime,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package discoverygo
+
+// DefaultEventKey returns the key events are deduplicated by when the
+// client's DeduplicateEvents is set without an EventKey: the event's name
+// and start dateTime. It returns an empty string, so the event is never
+// treated as a duplicate, if either is missing
+func DefaultEventKey(event map[string]any) string {
+	name := stringField(event, "name")
+	start := EventDates(event).Start.DateTime
+	if name == "" || start == "" {
+		return ""
+	}
+	return name + "\x00" + start
+}
+
+// eventFilter returns a function reporting whether an event should be
+// kept, dropping events with the same key as one already seen, if the
+// client's DeduplicateEvents is set. It returns nil otherwise
+func (d *DiscoveryClient) eventFilter() func(event map[string]any) bool {
+	if !d.DeduplicateEvents {
+		return nil
+	}
+	key := d.EventKey
+	if key == nil {
+		key = DefaultEventKey
+	}
+	seen := map[string]bool{}
+	return func(event map[string]any) bool {
+		k := key(event)
+		if k == "" {
+			return true
+		}
+		if seen[k] {
+			return false
+		}
+		seen[k] = true
+		return true
+	}
+}
+
+// filterEvents returns the events keep reports true for, or all of them
+// if keep is nil
+func filterEvents(
+	events []map[string]any,
+	keep func(event map[string]any) bool,
+) []map[string]any {
+	if keep == nil {
+		return events
+	}
+	var kept []map[string]any
+	for _, event := range events {
+		if keep(event) {
+			kept = append(kept, event)
+		}
+	}
+	return kept
+}
@@ -1,168 +1,717 @@
 package discoverygo
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"strconv"
+	"sync"
+	"time"
 )
 
 // DiscoveryApiUrl is the base URL to the Ticketmaster Discovery API
 const DiscoveryApiUrl = "https://app.ticketmaster.com/discovery/v2"
 
+// Version is the version of this library
+const Version = "0.1.0"
+
+// DefaultUserAgent is the User-Agent header sent with requests, unless the
+// client's UserAgent is set
+const DefaultUserAgent = "discoverygo/" + Version
+
+// ApiKeyHeader is the request header the API key is sent in when the
+// client's AuthMode is AuthHeader
+const ApiKeyHeader = "apikey"
+
+// AuthMode determines how the API key is sent with requests
+type AuthMode int
+
+const (
+	// AuthQuery sends the API key as the "apikey" query parameter (default)
+	AuthQuery AuthMode = iota
+	// AuthHeader sends the API key in the ApiKeyHeader request header,
+	// keeping it out of the request URL
+	AuthHeader
+)
+
 // DiscoveryClient is a client for the Ticketmaster Discovery API
 type DiscoveryClient struct {
 	// Base URL to the Discovery API
 	ApiUrl url.URL
 	// API key (consumer key)
 	ApiKey string
+	// HTTP client used to send requests. If nil, http.DefaultClient is used.
+	// NewDiscoveryClient sets a client using a shared transport from
+	// NewTransport, unless WithHTTPClient is given
+	HTTPClient *http.Client
+	// How the API key is sent with requests
+	AuthMode AuthMode
+	// Maximum number of times a request is retried after the API responds
+	// with 429 Too Many Requests, or another status in RetryPolicy. Zero
+	// disables retries
+	MaxRetries int
+	// Which responses are retried and how long to wait between attempts. If
+	// nil, only 429 responses are retried, with exponential backoff
+	RetryPolicy *RetryPolicy
+	// Logger receives debug logs for each request. If nil, nothing is logged
+	Logger *slog.Logger
+	// If true, the raw body of each response is kept on the returned
+	// PagedResponse, in its Raw field
+	KeepRawResponse bool
+	// User-Agent header sent with requests. If empty, DefaultUserAgent is used
+	UserAgent string
+	// Locale sent with requests that don't specify one, e.g. "en-us"
+	DefaultLocale string
+	// If set, successful responses are cached and reused for identical
+	// requests
+	Cache Cache
+	// How long responses are cached, unless their Cache-Control or Expires
+	// headers say otherwise. If zero, DefaultCacheTTL is used
+	CacheTTL time.Duration
+	// If true, reserved search syntax characters in the keyword parameter
+	// are escaped when a search is built. See EscapeKeyword
+	EscapeKeywords bool
+	// If true, no API key is sent with requests, e.g. when a proxy in front
+	// of the API adds it
+	DisableAPIKey bool
+	// If true, redirects aren't followed, and the redirect response is
+	// returned as an *APIError
+	DisableRedirects bool
+	// If true, the ETag of each response is stored and sent as If-None-Match
+	// with the next identical request. If the API responds 304 Not Modified,
+	// the stored body is returned, with the response's Meta.NotModified set
+	UseETags bool
+	// If true, a response body with data after its JSON value, e.g. two
+	// concatenated responses, is rejected with ErrTrailingData
+	StrictDecoding bool
+	// If true, responses with fields the typed models don't have are
+	// rejected. Only useful for detecting API changes, since the models
+	// don't cover every field
+	DisallowUnknownFields bool
+	// If true, failed requests return a *RequestError with the URL and
+	// duration of the request, wrapping the error it failed with
+	ErrorTiming bool
+	// Number of results of a search that can be paged through. A page can
+	// only be requested if page*size is less than it. If zero,
+	// DefaultMaxResultDepth is used
+	MaxResultDepth int
+	// Maximum number of requests AllEvents (pages) or GetEvents (events)
+	// has in flight at once. If less than two, AllEvents requests pages one
+	// at a time, and if less than one, GetEvents uses
+	// DefaultBatchConcurrency. Keep this low enough to stay within the
+	// API's rate limit, or set MaxRetries
+	Concurrency int
+	// If true, AllEvents and StreamEvents drop events with the same key as
+	// an earlier one, e.g. the same show listed by different sources
+	DeduplicateEvents bool
+	// Returns the key events are deduplicated by. If nil, DefaultEventKey
+	// is used
+	EventKey func(event map[string]any) string
+	// Called around each HTTP request, e.g. to record metrics
+	Hooks *Hooks
+
+	mu           sync.Mutex
+	rateLimit    RateLimit
+	hasRateLimit bool
+	timeout      time.Duration
+	transport    http.RoundTripper
+	etags        map[string]etagEntry
+}
+
+// retryBackoff is the initial delay before retrying a request that didn't
+// include a Retry-After header, when the RetryPolicy has no Backoff. It
+// doubles with each attempt, up to maxRetryBackoff
+var retryBackoff = time.Second
+
+// maxRetryBackoff is the longest delay before a retry when the RetryPolicy
+// has no Backoff
+const maxRetryBackoff = time.Minute
+
+// ErrMissingApiKey is returned by NewDiscoveryClient when no API key is
+// given, unless WithoutAPIKey is used
+var ErrMissingApiKey = errors.New("API key is required")
+
+// NewDiscoveryClient returns a DiscoveryClient for the given API key, pointed
+// at DiscoveryApiUrl. Options are applied in the order given. The key is
+// checked with ValidateAPIKey, and a warning is logged if it doesn't look
+// like a consumer key
+func NewDiscoveryClient(apiKey string, opts ...Option) (
+	*DiscoveryClient,
+	error,
+) {
+	apiUrl, err := url.Parse(DiscoveryApiUrl)
+	if err != nil {
+		return nil, err
+	}
+	d := &DiscoveryClient{ApiUrl: *apiUrl, ApiKey: apiKey}
+	for _, opt := range opts {
+		if err := opt(d); err != nil {
+			return nil, err
+		}
+	}
+	if !d.DisableAPIKey {
+		if err := ValidateAPIKey(apiKey); err != nil {
+			return nil, err
+		}
+		if !looksLikeAPIKey(apiKey) {
+			d.logger().Warn(
+				"API key doesn't look like a consumer key",
+				"length", len(apiKey),
+			)
+		}
+	}
+	transport := d.transport
+	if transport == nil && d.HTTPClient == nil {
+		transport = defaultTransport
+	}
+	if transport != nil || d.timeout > 0 {
+		// Copy the client so one passed to WithHTTPClient isn't modified
+		client := http.Client{}
+		if d.HTTPClient != nil {
+			client = *d.HTTPClient
+		}
+		if transport != nil {
+			client.Transport = transport
+		}
+		if d.timeout > 0 {
+			client.Timeout = d.timeout
+		}
+		d.HTTPClient = &client
+	}
+	return d, nil
+}
+
+// discardLogger is used when a client has no Logger
+var discardLogger = slog.New(discardHandler{})
+
+// discardHandler is a slog.Handler that drops all records
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// logger returns the logger requests should be logged with
+func (d *DiscoveryClient) logger() *slog.Logger {
+	if d.Logger != nil {
+		return d.Logger
+	}
+	return discardLogger
+}
+
+// httpClient returns the HTTP client requests should be sent with: a copy
+// of HTTPClient (or http.DefaultClient) with the client's redirect policy
+func (d *DiscoveryClient) httpClient() *http.Client {
+	client := http.DefaultClient
+	if d.HTTPClient != nil {
+		client = d.HTTPClient
+	}
+	withPolicy := *client
+	withPolicy.CheckRedirect = d.checkRedirect(client.CheckRedirect)
+	return &withPolicy
+}
+
+// newRequest returns a GET request for the given URL, with the API key (or
+// one from ContextWithAPIKey) applied according to the client's AuthMode,
+// the client's DefaultLocale applied if the URL has no locale, and the
+// User-Agent header set
+func (d *DiscoveryClient) newRequest(
+	ctx context.Context,
+	u url.URL,
+) (*http.Request, error) {
+	q := u.Query()
+	if d.DefaultLocale != "" && q.Get("locale") == "" {
+		q.Set("locale", d.DefaultLocale)
+	}
+	apiKey := d.requestAPIKey(ctx)
+	switch {
+	case d.AuthMode == AuthHeader, apiKey == "" && d.DisableAPIKey:
+		q.Del("apikey")
+	case apiKey != "":
+		q.Set("apikey", apiKey)
+	}
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		u.String(),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if d.AuthMode == AuthHeader && apiKey != "" {
+		req.Header.Set(ApiKeyHeader, apiKey)
+	}
+	userAgent := d.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept-Encoding", "gzip")
+	return req, nil
+}
+
+// do sends the given request, decoding the response body if it's gzipped.
+// If the client has a Cache, a cached response is returned if there is one,
+// and successful responses are cached. If the API responds with 429 Too
+// Many Requests (or another status retried by the RetryPolicy), the request
+// is retried up to MaxRetries times, waiting for the duration given by the
+// Retry-After header, or the policy's backoff if the header is absent.
+// Waiting is aborted if the request's context is done
+func (d *DiscoveryClient) do(req *http.Request) (*http.Response, error) {
+	if resp, ok := d.cachedResponse(req); ok {
+		return resp, nil
+	}
+	d.setIfNoneMatch(req)
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		d.onRequest(req, attempt)
+		start := time.Now()
+		resp, err := d.httpClient().Do(req.Clone(ctx))
+		duration := time.Since(start)
+		d.logRequest(req, resp, err, duration)
+		d.onResponse(req, attempt, resp, err, duration)
+		if err != nil {
+			return nil, err
+		}
+		d.recordRateLimit(resp.Header)
+		if !d.RetryPolicy.retryable(resp.StatusCode) ||
+			attempt >= d.MaxRetries {
+			resp, err = decompress(resp)
+			if err != nil {
+				return nil, err
+			}
+			resp, err = d.recordETag(req, resp)
+			if err != nil {
+				return nil, err
+			}
+			return d.cacheResponse(req, resp)
+		}
+		wait := retryAfter(resp.Header, d.RetryPolicy.backoff(attempt))
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		d.logger().Warn(
+			"Retrying request",
+			"status", resp.StatusCode,
+			"wait", wait,
+		)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// logRequest logs a single line at debug level for each request sent,
+// with its method, redacted URL, status code (or error) and duration
+func (d *DiscoveryClient) logRequest(
+	req *http.Request,
+	resp *http.Response,
+	err error,
+	duration time.Duration,
+) {
+	logger := d.logger()
+	if !logger.Enabled(req.Context(), slog.LevelDebug) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", RedactURL(*req.URL)),
+		slog.Duration("duration", duration),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	} else {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+	logger.LogAttrs(req.Context(), slog.LevelDebug, "Request", attrs...)
+}
+
+// retryAfter returns the delay given by a Retry-After header, which may be
+// either a number of seconds or an HTTP date. If the header is absent or
+// can't be parsed, fallback is returned
+func retryAfter(header http.Header, fallback time.Duration) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return fallback
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+		return 0
+	}
+	return fallback
+}
+
+// endpointUrl returns the URL to the given endpoint, with the API key
+// added as a query parameter unless it's empty or DisableAPIKey is set
+func (d *DiscoveryClient) endpointUrl(endpoint string) url.URL {
+	endpointUrl := d.ApiUrl.JoinPath(endpoint)
+	if apiKey := d.apiKey(); apiKey != "" {
+		q := endpointUrl.Query()
+		q.Set("apikey", apiKey)
+		endpointUrl.RawQuery = q.Encode()
+	}
+	return *endpointUrl
+}
+
+// maxResultDepth returns MaxResultDepth, or DefaultMaxResultDepth if it's
+// not set
+func (d *DiscoveryClient) maxResultDepth() int {
+	if d.MaxResultDepth > 0 {
+		return d.MaxResultDepth
+	}
+	return DefaultMaxResultDepth
+}
+
+// apiKey returns the API key to send with requests, which is empty if
+// DisableAPIKey is set
+func (d *DiscoveryClient) apiKey() string {
+	if d.DisableAPIKey {
+		return ""
+	}
+	return d.ApiKey
 }
 
 // EventsUrl returns the URL to the events endpoint, with
 // the API key added as a query parameter
 func (d *DiscoveryClient) EventsUrl() url.URL {
-	eventsUrl := d.ApiUrl.JoinPath("events")
-	if d.ApiKey == "" {
-		return *eventsUrl
-	}
-	q := eventsUrl.Query()
-	q.Set("apikey", d.ApiKey)
-	eventsUrl.RawQuery = q.Encode()
-	return *eventsUrl
+	return d.endpointUrl("events")
 }
 
 // VenuesUrl returns the URL to the venues endpoint, with
 // the API key added as a query parameter
 func (d *DiscoveryClient) VenuesUrl() url.URL {
-	venuesUrl := d.ApiUrl.JoinPath("venues")
-	if d.ApiKey == "" {
-		return *venuesUrl
+	return d.endpointUrl("venues")
+}
+
+// AttractionsUrl returns the URL to the attractions endpoint, with
+// the API key added as a query parameter
+func (d *DiscoveryClient) AttractionsUrl() url.URL {
+	return d.endpointUrl("attractions")
+}
+
+// ClassificationsUrl returns the URL to the classifications endpoint, with
+// the API key added as a query parameter
+func (d *DiscoveryClient) ClassificationsUrl() url.URL {
+	return d.endpointUrl("classifications")
+}
+
+// SuggestUrl returns the URL to the suggest endpoint, with
+// the API key added as a query parameter
+func (d *DiscoveryClient) SuggestUrl() url.URL {
+	return d.endpointUrl("suggest")
+}
+
+// Do sends a GET request for the given path, relative to the client's
+// ApiUrl, with the given query parameters. The API key, DefaultLocale and
+// headers are applied as for any other request, and rate-limited requests
+// are retried, but the response is returned as-is: its status code isn't
+// checked and the caller must close its body. It's meant for endpoints and
+// parameters this package doesn't otherwise support, e.g.
+//
+//	resp, err := client.Do(ctx, "events/G5diZfkn0B-bh/images", nil)
+func (d *DiscoveryClient) Do(
+	ctx context.Context,
+	path string,
+	params url.Values,
+) (*http.Response, error) {
+	u := d.ApiUrl.JoinPath(path)
+	u.RawQuery = params.Encode()
+	req, err := d.newRequest(ctx, *u)
+	if err != nil {
+		return nil, err
 	}
-	q := venuesUrl.Query()
-	q.Set("apikey", d.ApiKey)
-	venuesUrl.RawQuery = q.Encode()
-	return *venuesUrl
+	start := time.Now()
+	resp, err := d.do(req)
+	if err != nil {
+		return nil, d.requestError(req, start, err)
+	}
+	return resp, nil
 }
 
-// GetEvent returns an event by its ID
-// See: https://developer.ticketmaster.com/products-and-docs/apis/discovery-api/v2/#event-details-v2
-func (d *DiscoveryClient) GetEvent(id string) (*map[string]any, error) {
-	baseEventUrl := d.EventsUrl()
-	eventUrl := baseEventUrl.JoinPath(id)
-	log.Printf("Querying: %s", eventUrl)
-	resp, err := http.Get(eventUrl.String())
+// doRequest sends a GET request to the given URL and decodes the JSON
+// response body into out. Responses other than 200 OK are returned as an
+// *APIError, and bodies that aren't JSON as an error wrapping
+// ErrUnexpectedContentType. All of the client's typed methods go through
+// here
+func (d *DiscoveryClient) doRequest(
+	ctx context.Context,
+	u url.URL,
+	out any,
+) error {
+	req, err := d.newRequest(ctx, u)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	resp, err := d.do(req)
+	if err != nil {
+		return d.requestError(req, start, err)
+	}
 	defer resp.Body.Close()
 
+	notModified := resp.StatusCode == http.StatusNotModified && d.UseETags
+	if notModified && !d.replayETag(req, resp) {
+		return d.requestError(req, start, ErrNotModified)
+	}
+	if resp.StatusCode != http.StatusOK && !notModified {
+		return d.requestError(req, start, newAPIError(resp))
+	}
+	body, err := jsonBody(resp)
 	if err != nil {
-		log.Println(err)
-		return nil, err
+		return d.requestError(req, start, err)
 	}
-	log.Printf("Status code: %v", resp.StatusCode)
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf(
-			"Status code: %d: %s",
-			resp.StatusCode,
-			body,
-		)
+	decodeErr := d.decode(body, out)
+	if decodeErr != nil {
+		d.logger().Debug("Unable to decode response", "error", decodeErr)
+		return d.requestError(req, start, decodeErr)
+	}
+	if setter, ok := out.(metaSetter); ok {
+		meta := newResponseMeta(resp)
+		meta.NotModified = notModified
+		setter.setMeta(meta)
+	}
+	return nil
+}
+
+// rawSetter is implemented by responses that can hold their raw body
+type rawSetter interface {
+	setRaw(body []byte)
+}
+
+// ErrTrailingData is returned when the client's StrictDecoding is set and
+// a response body has data after its JSON value
+var ErrTrailingData = errors.New("Unexpected data after JSON response")
+
+// decode decodes the JSON response body into out. If KeepRawResponse is
+// set and out can hold its raw body, the body is kept on out (including
+// any trailing data). Unknown fields are rejected if DisallowUnknownFields
+// is set, and trailing data only if StrictDecoding is set
+func (d *DiscoveryClient) decode(body io.Reader, out any) error {
+	setter, keepRaw := out.(rawSetter)
+	keepRaw = keepRaw && d.KeepRawResponse
+	var raw []byte
+	if keepRaw {
+		var err error
+		raw, err = io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(raw)
+	}
+	decoder := json.NewDecoder(body)
+	if d.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
 	}
+	if err := decoder.Decode(out); err != nil {
+		return err
+	}
+	if d.StrictDecoding {
+		if _, err := decoder.Token(); err != io.EOF {
+			return ErrTrailingData
+		}
+	}
+	if keepRaw {
+		setter.setRaw(raw)
+	}
+	return nil
+}
+
+// GetEvent returns an event by its ID
+// See: https://developer.ticketmaster.com/products-and-docs/apis/discovery-api/v2/#event-details-v2
+func (d *DiscoveryClient) GetEvent(id string) (*map[string]any, error) {
+	return d.GetEventContext(context.Background(), id)
+}
+
+// GetEventContext returns an event by its ID. The request is bound to
+// the given context, so it is aborted if the context is cancelled or its
+// deadline passes
+func (d *DiscoveryClient) GetEventContext(
+	ctx context.Context,
+	id string,
+) (*map[string]any, error) {
 	var rs map[string]any
-	decodeErr := json.NewDecoder(resp.Body).Decode(&rs)
-	if decodeErr != nil {
-		log.Println(decodeErr)
-		return nil, decodeErr
+	if err := d.doRequest(ctx, d.eventUrl(id), &rs); err != nil {
+		return nil, err
 	}
 	return &rs, nil
 }
 
+// GetEventTyped returns an event by its ID, decoded into an Event
+func (d *DiscoveryClient) GetEventTyped(id string) (*Event, error) {
+	return d.GetEventTypedContext(context.Background(), id)
+}
+
+// GetEventTypedContext returns an event by its ID, decoded into an Event.
+// The request is bound to the given context
+func (d *DiscoveryClient) GetEventTypedContext(
+	ctx context.Context,
+	id string,
+) (*Event, error) {
+	var rs Event
+	if err := d.doRequest(ctx, d.eventUrl(id), &rs); err != nil {
+		return nil, err
+	}
+	return &rs, nil
+}
+
+// eventUrl returns the URL to the event with the given ID
+func (d *DiscoveryClient) eventUrl(id string) url.URL {
+	baseEventUrl := d.EventsUrl()
+	return *baseEventUrl.JoinPath(id)
+}
+
 // SearchEvents returns a list of events matching the given query parameters
 func (d *DiscoveryClient) SearchEvents(
 	queryParams QueryParams,
 ) (*PagedResponse, error) {
-	eventsUrl, err := queryParams.UpdateURL(d.EventsUrl(), d.ApiKey)
+	return d.SearchEventsContext(context.Background(), queryParams)
+}
+
+// SearchEventsContext returns a list of events matching the given query
+// parameters. The request is bound to the given context
+func (d *DiscoveryClient) SearchEventsContext(
+	ctx context.Context,
+	queryParams QueryParams,
+) (*PagedResponse, error) {
+	eventsUrl, err := d.searchURL(d.EventsUrl(), queryParams)
 	if err != nil {
 		return nil, err
 	}
-	resp, err := http.Get(eventsUrl.String())
-	defer resp.Body.Close()
-
-	if err != nil {
-		log.Println(err)
+	var rs PagedResponse
+	if err := d.doRequest(ctx, *eventsUrl, &rs); err != nil {
 		return nil, err
 	}
+	rs.params = &queryParams
+	rs.eventSearch = true
+	return &rs, nil
+}
 
-	log.Printf("Status code: %v", resp.StatusCode)
+// Ping checks that the Discovery API is reachable and accepts the client's
+// API key, with a search for a single event. The response body isn't
+// decoded, and the client's Cache and ETags are bypassed so the API is
+// always reached. It returns an *APIError if the API responds with an
+// error, which matches ErrUnauthorized for an invalid API key
+func (d *DiscoveryClient) Ping(ctx context.Context) error {
+	ctx = context.WithValue(ctx, uncachedContextKey{}, true)
+	resp, err := d.Do(ctx, "events", url.Values{"size": {"1"}})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Status code: %d: %s", resp.StatusCode, body)
+		return newAPIError(resp)
 	}
-	//var rs map[string]any
-	var rs PagedResponse
-	decodeErr := json.NewDecoder(resp.Body).Decode(&rs)
-	if decodeErr != nil {
-		log.Println(decodeErr)
-		return nil, decodeErr
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// CountEvents returns the number of events matching the given query
+// parameters, without fetching more than one of them
+func (d *DiscoveryClient) CountEvents(queryParams QueryParams) (int, error) {
+	return d.CountEventsContext(context.Background(), queryParams)
+}
+
+// CountEventsContext returns the number of events matching the given query
+// parameters. The request is bound to the given context
+func (d *DiscoveryClient) CountEventsContext(
+	ctx context.Context,
+	queryParams QueryParams,
+) (int, error) {
+	queryParams.Page = 0
+	queryParams.Size = 1
+	rs, err := d.SearchEventsContext(ctx, queryParams)
+	if err != nil {
+		return 0, err
 	}
-	return &rs, nil
+	return rs.Page.TotalElements, nil
 }
 
-// QueryParams is a struct that holds the query parameters for the Discovery API
+// QueryParams is a struct that holds the query parameters for the Discovery
+// API. Slice fields are repeatable: each value is sent as its own parameter
 type QueryParams struct {
-	Id                 string `json:"id,omitempty"`
-	Sort               string `json:"sort,omitempty"`
-	Page               string `json:"page,omitempty"`
-	Size               string `json:"size,omitempty"`
-	Locale             string `json:"locale,omitempty"`
-	Keyword            string `json:"keyword,omitempty"`
-	IncludeTest        string `json:"includeTest,omitempty"`
-	IncludeTBA         string `json:"includeTBA,omitempty"`
-	IncludeTBD         string `json:"includeTBD,omitempty"`
-	VenueID            string `json:"venueId,omitempty"`
-	StartDateTime      string `json:"startDateTime,omitempty"`
-	EndDateTime        string `json:"endDateTime,omitempty"`
-	CountryCode        string `json:"countryCode,omitempty"`
-	StateCode          string `json:"stateCode,omitempty"`
-	AttractionID       string `json:"attractionId,omitempty"`
-	SegmentID          string `json:"segmentId,omitempty"`
-	SegmentName        string `json:"segmentName,omitempty"`
-	ClassificationID   string `json:"classificationId,omitempty"`
-	ClassificationName string `json:"classificationName,omitempty"`
-	MarketID           string `json:"marketId,omitempty"`
-	PromoterID         string `json:"promoterId,omitempty"`
-	DmaID              string `json:"dmaId,omitempty"`
-	LatLong            string `json:"latlong,omitempty"`
-	Radius             string `json:"radius,omitempty"`
-	Unit               string `json:"unit,omitempty"`
-}
-
-// UpdateURL updates the given URL with the query parameters, and includes
-// the API key as a query parameter
+	Id                     string        `json:"id,omitempty"`
+	Sort                   string        `json:"sort,omitempty"`
+	Page                   int           `json:"page,omitempty"`
+	Size                   int           `json:"size,omitempty"`
+	Locale                 string        `json:"locale,omitempty"`
+	Keyword                string        `json:"keyword,omitempty"`
+	IncludeTest            IncludeFilter `json:"includeTest,omitempty"`
+	IncludeTBA             IncludeFilter `json:"includeTBA,omitempty"`
+	IncludeTBD             IncludeFilter `json:"includeTBD,omitempty"`
+	IncludeSpellcheck      IncludeFilter `json:"includeSpellcheck,omitempty"`
+	IncludeFamily          IncludeFilter `json:"includeFamily,omitempty"`
+	IncludeLicensedContent string        `json:"includeLicensedContent,omitempty"`
+	VenueID                []string      `json:"venueId,omitempty"`
+	StartDateTime          string        `json:"startDateTime,omitempty"`
+	EndDateTime            string        `json:"endDateTime,omitempty"`
+	CountryCode            string        `json:"countryCode,omitempty"`
+	StateCode              string        `json:"stateCode,omitempty"`
+	PostalCode             string        `json:"postalCode,omitempty"`
+	AttractionID           []string      `json:"attractionId,omitempty"`
+	SegmentID              []string      `json:"segmentId,omitempty"`
+	SegmentName            string        `json:"segmentName,omitempty"`
+	GenreID                []string      `json:"genreId,omitempty"`
+	SubGenreID             []string      `json:"subGenreId,omitempty"`
+	ClassificationID       []string      `json:"classificationId,omitempty"`
+	ClassificationName     string        `json:"classificationName,omitempty"`
+	MarketID               []string      `json:"marketId,omitempty"`
+	PromoterID             string        `json:"promoterId,omitempty"`
+	DmaID                  string        `json:"dmaId,omitempty"`
+	LatLong                string        `json:"latlong,omitempty"`
+	GeoPoint               string        `json:"geoPoint,omitempty"`
+	Radius                 string        `json:"radius,omitempty"`
+	Unit                   string        `json:"unit,omitempty"`
+	Source                 string        `json:"source,omitempty"`
+	Resource               []string      `json:"resource,omitempty"`
+	PreferredCountry       string        `json:"preferredCountry,omitempty"`
+	// Extra holds any other query parameters to send, e.g. ones the API
+	// supports that don't have a field yet. They're added after the other
+	// fields, which take precedence for the same key. An apikey is never
+	// sent from Extra
+	Extra url.Values `json:"-"`
+}
+
+// UpdateURL updates the given URL with the query parameters from Values,
+// and includes the API key as a query parameter unless it's empty. The
+// query parameters are validated first
 func (q QueryParams) UpdateURL(u url.URL, apikey string) (*url.URL, error) {
-	var qp map[string]string
-	inrec, err := json.Marshal(q)
-	if err != nil {
+	if err := q.Validate(); err != nil {
 		return nil, err
 	}
-	unmarshalError := json.Unmarshal(inrec, &qp)
-	if unmarshalError != nil {
-		return nil, unmarshalError
-	}
-
 	query := u.Query()
-	query.Set("apikey", apikey)
-	for field, val := range qp {
-		if val != "" {
-			query.Add(field, val)
+	if apikey != "" {
+		query.Set("apikey", apikey)
+	}
+	for field, values := range q.Values() {
+		for _, value := range values {
+			query.Add(field, value)
 		}
 	}
 	u.RawQuery = query.Encode()
 	return &u, nil
 }
 
-// redactUrl replaces the API key in the given URL with the string "REDACTED"
-func redactUrl(u url.URL) string {
+// RedactURL replaces the API key in the given URL with the string
+// "REDACTED", so the URL can be logged safely
+func RedactURL(u url.URL) string {
 	query := u.Query()
 	_, exists := query["apikey"]
 	if exists {
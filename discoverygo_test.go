@@ -1,12 +1,38 @@
 package discoverygo
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
+// newTestClient returns a DiscoveryClient pointed at a test server
+// running the given handler
+func newTestClient(t *testing.T, handler http.HandlerFunc) *DiscoveryClient {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	apiUrl, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Unable to parse url: %v", err)
+	}
+	return &DiscoveryClient{ApiUrl: *apiUrl, ApiKey: "12345"}
+}
+
 func TestApiUrl(t *testing.T) {
 	expectedUrl := "https://app.ticketmaster.com/discovery/v2"
 	apiUrl, err := url.Parse(expectedUrl)
@@ -55,8 +81,8 @@ func TestAddApiKey(t *testing.T) {
 	fmt.Println(eventsUrlWithApiKey.String())
 }
 
-func TestGetEvent(t *testing.T) {
-	eventJson := `{
+// testEventJson is an example response from the event details endpoint
+const testEventJson = `{
   "_embedded": {
     "venues": [
       {
@@ -371,8 +397,10 @@ func TestGetEvent(t *testing.T) {
   "type": "event",
   "url": "http://ticketmaster.com/event/3B00506AA4EA161B"
 }`
+
+func TestGetEvent(t *testing.T) {
 	var event map[string]any
-	decodeErr := json.Unmarshal([]byte(eventJson), &event)
+	decodeErr := json.Unmarshal([]byte(testEventJson), &event)
 	if decodeErr != nil {
 		t.Errorf("Error decoding event json: %v", decodeErr)
 	}
@@ -385,7 +413,7 @@ func TestUrlRedacted(t *testing.T) {
 	apiKey := "1234"
 	dc := DiscoveryClient{ApiUrl: *apiUrl, ApiKey: apiKey}
 
-	urlStr := redactUrl(dc.EventsUrl())
+	urlStr := RedactURL(dc.EventsUrl())
 	if urlStr != fmt.Sprintf(
 		"%s/events?apikey=%s",
 		DiscoveryApiUrl,
@@ -398,3 +426,2990 @@ func TestUrlRedacted(t *testing.T) {
 		)
 	}
 }
+
+func TestSearchEventsContextCancelled(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request, got: %v", r.URL)
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := dc.SearchEventsContext(ctx, QueryParams{Keyword: "radiohead"})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected %v, got: %v", context.Canceled, err)
+	}
+}
+
+func TestGetEventContext(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/events/G5diZfkn0B-bh" {
+			t.Errorf("Expected /events/G5diZfkn0B-bh, got: %v", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"id": "G5diZfkn0B-bh", "name": "Radiohead"}`)
+	})
+	event, err := dc.GetEventContext(context.Background(), "G5diZfkn0B-bh")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if (*event)["name"] != "Radiohead" {
+		t.Errorf("Expected %v, got: %v", "Radiohead", (*event)["name"])
+	}
+}
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestCustomHTTPClient(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"page": {"size": 20}}`)
+	})
+	called := false
+	dc.HTTPClient = &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			called = true
+			return http.DefaultTransport.RoundTrip(r)
+		}),
+	}
+	if _, err := dc.SearchEvents(QueryParams{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !called {
+		t.Errorf("Expected request to be sent with the custom HTTP client")
+	}
+}
+
+func TestNewDiscoveryClient(t *testing.T) {
+	dc, err := NewDiscoveryClient("12345")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if dc.ApiUrl.String() != DiscoveryApiUrl {
+		t.Errorf("Expected %v, got: %v", DiscoveryApiUrl, dc.ApiUrl.String())
+	}
+	if dc.ApiKey != "12345" {
+		t.Errorf("Expected %v, got: %v", "12345", dc.ApiKey)
+	}
+}
+
+func TestNewDiscoveryClientMissingKey(t *testing.T) {
+	_, err := NewDiscoveryClient("")
+	if !errors.Is(err, ErrMissingApiKey) {
+		t.Errorf("Expected %v, got: %v", ErrMissingApiKey, err)
+	}
+}
+
+func TestNewDiscoveryClientInvalidKey(t *testing.T) {
+	if _, err := NewDiscoveryClient("   "); !errors.Is(err, ErrMissingApiKey) {
+		t.Errorf("Expected %v, got: %v", ErrMissingApiKey, err)
+	}
+	_, err := NewDiscoveryClient("abcdefghijklmnopqrstuvwxyz012345\n")
+	if !errors.Is(err, ErrInvalidApiKey) {
+		t.Errorf("Expected %v, got: %v", ErrInvalidApiKey, err)
+	}
+
+	var logs strings.Builder
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	if _, err := NewDiscoveryClient("short-key", WithLogger(logger)); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(logs.String(), "doesn't look like") {
+		t.Errorf("Expected a warning, got: %q", logs.String())
+	}
+	logs.Reset()
+	key := "abcdefghijklmnopqrstuvwxyz012345"
+	if _, err := NewDiscoveryClient(key, WithLogger(logger)); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("Expected no warning, got: %q", logs.String())
+	}
+}
+
+func TestNewDiscoveryClientOptions(t *testing.T) {
+	httpClient := &http.Client{}
+	dc, err := NewDiscoveryClient(
+		"12345",
+		WithBaseURL("http://localhost:8080/discovery/v2"),
+		WithHTTPClient(httpClient),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedUrl := "http://localhost:8080/discovery/v2/events?apikey=12345"
+	eventsUrl := dc.EventsUrl()
+	if eventsUrl.String() != expectedUrl {
+		t.Errorf("Expected %v, got: %v", expectedUrl, eventsUrl.String())
+	}
+	if dc.HTTPClient != httpClient {
+		t.Errorf("Expected custom HTTP client to be set")
+	}
+}
+
+func TestAuthHeader(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("apikey") {
+			t.Errorf("Expected no apikey query parameter, got: %v", r.URL)
+		}
+		if r.Header.Get(ApiKeyHeader) != "12345" {
+			t.Errorf(
+				"Expected %v, got: %v",
+				"12345",
+				r.Header.Get(ApiKeyHeader),
+			)
+		}
+		fmt.Fprint(w, `{"page": {"size": 20}}`)
+	})
+	dc.AuthMode = AuthHeader
+	if _, err := dc.SearchEvents(QueryParams{Keyword: "radiohead"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestAuthQuery(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("apikey") != "12345" {
+			t.Errorf(
+				"Expected %v, got: %v",
+				"12345",
+				r.URL.Query().Get("apikey"),
+			)
+		}
+		if r.Header.Get(ApiKeyHeader) != "" {
+			t.Errorf("Expected no API key header")
+		}
+		fmt.Fprint(w, `{"id": "G5diZfkn0B-bh"}`)
+	})
+	if _, err := dc.GetEvent("G5diZfkn0B-bh"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestRetryOnTooManyRequests(t *testing.T) {
+	attempts := 0
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"page": {"size": 20}}`)
+	})
+	dc.MaxRetries = 2
+	if _, err := dc.SearchEvents(QueryParams{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected %v, got: %v", 3, attempts)
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	attempts := 0
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	dc.MaxRetries = 1
+	if _, err := dc.SearchEvents(QueryParams{}); err == nil {
+		t.Errorf("Expected an error after exhausting retries")
+	}
+	if attempts != 2 {
+		t.Errorf("Expected %v, got: %v", 2, attempts)
+	}
+}
+
+func TestRetryContextCancelled(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	dc.MaxRetries = 1
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		50*time.Millisecond,
+	)
+	defer cancel()
+	_, err := dc.SearchEventsContext(ctx, QueryParams{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected %v, got: %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	statuses := []int{
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusOK,
+	}
+	attempts := 0
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		status := statuses[attempts]
+		attempts++
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		fmt.Fprint(w, `{"page": {"size": 20}}`)
+	})
+	var backoffs []int
+	dc.MaxRetries = 2
+	dc.RetryPolicy = &RetryPolicy{
+		RetryableStatuses: []int{
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+		},
+		Backoff: func(attempt int) time.Duration {
+			backoffs = append(backoffs, attempt)
+			return time.Millisecond
+		},
+	}
+	if _, err := dc.SearchEvents(QueryParams{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected %v, got: %v", 3, attempts)
+	}
+	if !reflect.DeepEqual(backoffs, []int{0, 1}) {
+		t.Errorf("Expected %v, got: %v", []int{0, 1}, backoffs)
+	}
+}
+
+func TestDefaultBackoff(t *testing.T) {
+	tests := map[int]time.Duration{
+		0:       retryBackoff,
+		1:       2 * retryBackoff,
+		3:       8 * retryBackoff,
+		64:      maxRetryBackoff,
+		1 << 20: maxRetryBackoff,
+	}
+	var policy *RetryPolicy
+	for attempt, expected := range tests {
+		if backoff := policy.backoff(attempt); backoff != expected {
+			t.Errorf("%d: Expected %v, got: %v", attempt, expected, backoff)
+		}
+	}
+}
+
+func TestRetryPolicyNotRetryable(t *testing.T) {
+	attempts := 0
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	dc.MaxRetries = 2
+	_, err := dc.SearchEvents(QueryParams{})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) ||
+		apiErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Expected *APIError with status 500, got: %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("Expected %v, got: %v", 1, attempts)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	fallback := 5 * time.Second
+	tests := map[string]time.Duration{
+		"":                              fallback,
+		"3":                             3 * time.Second,
+		"invalid":                       fallback,
+		"Wed, 21 Oct 2015 07:28:00 GMT": 0,
+	}
+	for value, expected := range tests {
+		header := http.Header{}
+		if value != "" {
+			header.Set("Retry-After", value)
+		}
+		if got := retryAfter(header, fallback); got != expected {
+			t.Errorf("%q: Expected %v, got: %v", value, expected, got)
+		}
+	}
+}
+
+func TestLastRateLimit(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Rate-Limit", "5000")
+		w.Header().Set("Rate-Limit-Available", "4999")
+		w.Header().Set("Rate-Limit-Reset", "1453180594367")
+		fmt.Fprint(w, `{"page": {"size": 20}}`)
+	})
+	if _, ok := dc.LastRateLimit(); ok {
+		t.Errorf("Expected no rate limit before the first request")
+	}
+	if _, err := dc.SearchEvents(QueryParams{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rateLimit, ok := dc.LastRateLimit()
+	if !ok {
+		t.Fatalf("Expected rate limit to be recorded")
+	}
+	expected := RateLimit{Limit: 5000, Available: 4999, Reset: 1453180594367}
+	if rateLimit != expected {
+		t.Errorf("Expected %+v, got: %+v", expected, rateLimit)
+	}
+	if rateLimit.ResetTime().UnixMilli() != 1453180594367 {
+		t.Errorf(
+			"Expected %v, got: %v",
+			1453180594367,
+			rateLimit.ResetTime().UnixMilli(),
+		)
+	}
+}
+
+func TestGetEventTyped(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, testEventJson)
+	})
+	event, err := dc.GetEventTyped("G5diZfkn0B-bh")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if event.ID != "G5diZfkn0B-bh" {
+		t.Errorf("Expected %v, got: %v", "G5diZfkn0B-bh", event.ID)
+	}
+	if event.Name != "Radiohead" {
+		t.Errorf("Expected %v, got: %v", "Radiohead", event.Name)
+	}
+	if event.Dates.Start.DateTime != "2016-07-27T23:30:00Z" {
+		t.Errorf(
+			"Expected %v, got: %v",
+			"2016-07-27T23:30:00Z",
+			event.Dates.Start.DateTime,
+		)
+	}
+	expectedPrice := PriceRange{
+		Type:     "standard",
+		Currency: "USD",
+		Min:      80,
+		Max:      80,
+	}
+	if len(event.PriceRanges) != 1 || event.PriceRanges[0] != expectedPrice {
+		t.Errorf("Expected %+v, got: %+v", expectedPrice, event.PriceRanges)
+	}
+	if len(event.Classifications) != 1 ||
+		event.Classifications[0].SubGenre.Name != "Alternative Rock" {
+		t.Errorf(
+			"Expected sub-genre Alternative Rock, got: %+v",
+			event.Classifications,
+		)
+	}
+	if event.Promoter == nil || event.Promoter.ID != "494" {
+		t.Errorf("Expected promoter 494, got: %+v", event.Promoter)
+	}
+}
+
+// testVenueJson is an example response from the venue details endpoint
+const testVenueJson = `{
+  "name": "Madison Square Garden",
+  "type": "venue",
+  "id": "KovZpZA7AAEA",
+  "test": false,
+  "url": "http://ticketmaster.com/venue/483329",
+  "locale": "en-us",
+  "postalCode": "10001",
+  "timezone": "America/New_York",
+  "city": {"name": "New York"},
+  "state": {"name": "New York", "stateCode": "NY"},
+  "country": {"name": "United States Of America", "countryCode": "US"},
+  "address": {"line1": "7th Ave & 32nd Street"},
+  "location": {"longitude": "-73.99160060", "latitude": "40.74970620"},
+  "markets": [{"id": "35"}, {"id": "51"}],
+  "dmas": [{"id": 200}, {"id": 345}],
+  "_links": {
+    "self": {"href": "/discovery/v2/venues/KovZpZA7AAEA?locale=en-us"}
+  }
+}`
+
+func TestGetVenue(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/venues/KovZpZA7AAEA" {
+			t.Errorf("Expected /venues/KovZpZA7AAEA, got: %v", r.URL.Path)
+		}
+		fmt.Fprint(w, testVenueJson)
+	})
+	venue, err := dc.GetVenue("KovZpZA7AAEA")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if venue.Name != "Madison Square Garden" {
+		t.Errorf("Expected %v, got: %v", "Madison Square Garden", venue.Name)
+	}
+	if venue.State.StateCode != "NY" {
+		t.Errorf("Expected %v, got: %v", "NY", venue.State.StateCode)
+	}
+	expectedLocation := Location{Latitude: 40.7497062, Longitude: -73.9916006}
+	if venue.Location == nil || *venue.Location != expectedLocation {
+		t.Errorf("Expected %+v, got: %+v", expectedLocation, venue.Location)
+	}
+	if len(venue.Dmas) != 2 || venue.Dmas[1].ID != 345 {
+		t.Errorf("Expected dmas 200 and 345, got: %+v", venue.Dmas)
+	}
+}
+
+func TestSearchVenues(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/venues" {
+			t.Errorf("Expected /venues, got: %v", r.URL.Path)
+		}
+		if r.URL.Query().Get("stateCode") != "NY" {
+			t.Errorf(
+				"Expected %v, got: %v",
+				"NY",
+				r.URL.Query().Get("stateCode"),
+			)
+		}
+		fmt.Fprintf(
+			w,
+			`{"_embedded": {"venues": [%s]}, "page": {"size": 20, "totalElements": 1, "totalPages": 1, "number": 0}}`,
+			testVenueJson,
+		)
+	})
+	rs, err := dc.SearchVenues(QueryParams{Keyword: "garden", StateCode: "NY"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rs.Embedded.Venues) != 1 {
+		t.Fatalf("Expected %v, got: %v", 1, len(rs.Embedded.Venues))
+	}
+	if rs.Embedded.Venues[0]["id"] != "KovZpZA7AAEA" {
+		t.Errorf(
+			"Expected %v, got: %v",
+			"KovZpZA7AAEA",
+			rs.Embedded.Venues[0]["id"],
+		)
+	}
+}
+
+// testAttractionJson is an example response from the attraction
+// details endpoint
+const testAttractionJson = `{
+  "name": "Radiohead",
+  "type": "attraction",
+  "id": "K8vZ91713wV",
+  "test": false,
+  "url": "http://ticketmaster.com/artist/763468",
+  "locale": "en-us",
+  "classifications": [
+    {
+      "primary": true,
+      "segment": {"id": "KZFzniwnSyZfZ7v7nJ", "name": "Music"},
+      "genre": {"id": "KnvZfZ7vAeA", "name": "Rock"},
+      "subGenre": {"id": "KZazBEonSMnZfZ7v6dt", "name": "Alternative Rock"}
+    }
+  ],
+  "upcomingEvents": {"_total": 4, "ticketmaster": 4}
+}`
+
+func TestAttractionsUrl(t *testing.T) {
+	apiUrl, _ := url.Parse(DiscoveryApiUrl)
+	dc := DiscoveryClient{ApiUrl: *apiUrl, ApiKey: "12345"}
+	attractionsUrl := dc.AttractionsUrl()
+	expectedUrl := DiscoveryApiUrl + "/attractions?apikey=12345"
+	if attractionsUrl.String() != expectedUrl {
+		t.Errorf("Expected %v, got: %v", expectedUrl, attractionsUrl.String())
+	}
+}
+
+func TestGetAttraction(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/attractions/K8vZ91713wV" {
+			t.Errorf("Expected /attractions/K8vZ91713wV, got: %v", r.URL.Path)
+		}
+		fmt.Fprint(w, testAttractionJson)
+	})
+	attraction, err := dc.GetAttraction("K8vZ91713wV")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if attraction.Name != "Radiohead" {
+		t.Errorf("Expected %v, got: %v", "Radiohead", attraction.Name)
+	}
+	if attraction.UpcomingEvents["_total"] != 4 {
+		t.Errorf(
+			"Expected %v, got: %v",
+			4,
+			attraction.UpcomingEvents["_total"],
+		)
+	}
+}
+
+func TestSearchAttractions(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/attractions" {
+			t.Errorf("Expected /attractions, got: %v", r.URL.Path)
+		}
+		fmt.Fprintf(
+			w,
+			`{"_embedded": {"attractions": [%s]}, "page": {"size": 20, "totalElements": 1, "totalPages": 1, "number": 0}}`,
+			testAttractionJson,
+		)
+	})
+	rs, err := dc.SearchAttractions(QueryParams{Keyword: "radiohead"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rs.Embedded.Attractions) != 1 {
+		t.Fatalf("Expected %v, got: %v", 1, len(rs.Embedded.Attractions))
+	}
+}
+
+func TestGetClassification(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/classifications/KZFzniwnSyZfZ7v7nJ" {
+			t.Errorf(
+				"Expected /classifications/KZFzniwnSyZfZ7v7nJ, got: %v",
+				r.URL.Path,
+			)
+		}
+		fmt.Fprint(
+			w,
+			`{"segment": {"id": "KZFzniwnSyZfZ7v7nJ", "name": "Music"}}`,
+		)
+	})
+	classification, err := dc.GetClassification("KZFzniwnSyZfZ7v7nJ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if classification.Segment.Name != "Music" {
+		t.Errorf("Expected %v, got: %v", "Music", classification.Segment.Name)
+	}
+}
+
+func TestSearchClassifications(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/classifications" {
+			t.Errorf("Expected /classifications, got: %v", r.URL.Path)
+		}
+		fmt.Fprint(
+			w,
+			`{"_embedded": {"classifications": [{"segment": {"id": "KZFzniwnSyZfZ7v7nJ", "name": "Music"}}]}, "page": {"size": 20, "totalElements": 1, "totalPages": 1, "number": 0}}`,
+		)
+	})
+	rs, err := dc.SearchClassifications(QueryParams{Keyword: "music"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rs.Embedded.Classifications) != 1 {
+		t.Fatalf(
+			"Expected %v, got: %v",
+			1,
+			len(rs.Embedded.Classifications),
+		)
+	}
+}
+
+// newPagedTestClient returns a DiscoveryClient pointed at a test server
+// that serves totalPages pages of events, each with one event whose ID
+// is the page number
+func newPagedTestClient(t *testing.T, totalPages int) *DiscoveryClient {
+	t.Helper()
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		number := 0
+		if page := r.URL.Query().Get("page"); page != "" {
+			fmt.Sscan(page, &number)
+		}
+		links := fmt.Sprintf(
+			`{"self": {"href": "/events?page=%d&size=1"}`,
+			number,
+		)
+		if number+1 < totalPages {
+			links += fmt.Sprintf(
+				`, "next": {"href": "/events?page=%d&size=1"}`,
+				number+1,
+			)
+		}
+		links += "}"
+		fmt.Fprintf(
+			w,
+			`{"_links": %s, "_embedded": {"events": [{"id": "%d"}]}, "page": {"size": 1, "totalElements": %d, "totalPages": %d, "number": %d}}`,
+			links,
+			number,
+			totalPages,
+			totalPages,
+			number,
+		)
+	})
+}
+
+func TestEventsIterator(t *testing.T) {
+	dc := newPagedTestClient(t, 3)
+	var ids []any
+	it := dc.EventsIterator(QueryParams{Size: 1})
+	for it.Next() {
+		for _, event := range it.Page().Embedded.Events {
+			ids = append(ids, event["id"])
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fmt.Sprint(ids) != "[0 1 2]" {
+		t.Errorf("Expected %v, got: %v", "[0 1 2]", ids)
+	}
+	if it.Next() {
+		t.Errorf("Expected Next to return false after the last page")
+	}
+}
+
+func TestEventsIteratorError(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	it := dc.EventsIterator(QueryParams{})
+	if it.Next() {
+		t.Errorf("Expected Next to return false")
+	}
+	if it.Err() == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestEachEvent(t *testing.T) {
+	dc := newPagedTestClient(t, 3)
+	count := 0
+	err := dc.EachEvent(QueryParams{Size: 1}, func(event map[string]any) error {
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected %v, got: %v", 3, count)
+	}
+}
+
+func TestAllEvents(t *testing.T) {
+	dc := newPagedTestClient(t, 4)
+	events, err := dc.AllEvents(QueryParams{Size: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(events) != 4 {
+		t.Errorf("Expected %v, got: %v", 4, len(events))
+	}
+}
+
+func TestAllEventsMaxPageDepth(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(
+			w,
+			`{"_links": {"next": {"href": "/events?page=6&size=200"}}, "_embedded": {"events": [{"id": "1"}]}, "page": {"size": 200, "totalElements": 5000, "totalPages": 25, "number": 5}}`,
+		)
+	})
+	events, err := dc.AllEvents(QueryParams{Size: 200, Page: 5})
+	if !errors.Is(err, ErrMaxPageDepth) {
+		t.Errorf("Expected %v, got: %v", ErrMaxPageDepth, err)
+	}
+	if len(events) != 1 {
+		t.Errorf("Expected %v, got: %v", 1, len(events))
+	}
+}
+
+func TestLogger(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "G5diZfkn0B-bh"}`)
+	})
+	var buf bytes.Buffer
+	dc.Logger = slog.New(
+		slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+	if _, err := dc.GetEvent("G5diZfkn0B-bh"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	logs := buf.String()
+	if !strings.Contains(logs, "status=200") {
+		t.Errorf("Expected status code to be logged, got: %v", logs)
+	}
+	if strings.Contains(logs, "12345") {
+		t.Errorf("Expected API key to be redacted, got: %v", logs)
+	}
+}
+
+func TestConnectionRefused(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	apiUrl, _ := url.Parse(server.URL)
+	server.Close()
+	dc := &DiscoveryClient{ApiUrl: *apiUrl, ApiKey: "12345"}
+	page := &PagedResponse{
+		Links: Links{
+			Next: Link{Href: "/events?page=1"},
+			Prev: Link{Href: "/events?page=0"},
+		},
+	}
+
+	if _, err := dc.GetEvent("G5diZfkn0B-bh"); err == nil {
+		t.Errorf("GetEvent: Expected an error")
+	}
+	if _, err := dc.SearchEvents(QueryParams{}); err == nil {
+		t.Errorf("SearchEvents: Expected an error")
+	}
+	if _, err := page.NextPage(dc); err == nil {
+		t.Errorf("NextPage: Expected an error")
+	}
+	if _, err := page.PreviousPage(dc); err == nil {
+		t.Errorf("PreviousPage: Expected an error")
+	}
+}
+
+func TestSpellcheck(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("includeSpellcheck") != "yes" {
+			t.Errorf(
+				"Expected %v, got: %v",
+				"yes",
+				r.URL.Query().Get("includeSpellcheck"),
+			)
+		}
+		fmt.Fprint(
+			w,
+			`{"spellcheck": {"query": "radiohed", "suggestions": [{"suggestion": "radiohead", "score": 0.9}]}, "page": {"size": 20}}`,
+		)
+	})
+	rs, err := dc.SearchEvents(
+		QueryParams{Keyword: "radiohed", IncludeSpellcheck: "yes"},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	suggestions := rs.Spellcheck.Strings()
+	if len(suggestions) != 1 || suggestions[0] != "radiohead" {
+		t.Errorf("Expected %v, got: %v", "[radiohead]", suggestions)
+	}
+	var empty *Spellcheck
+	if empty.Strings() != nil {
+		t.Errorf("Expected nil suggestions for a nil spellcheck")
+	}
+}
+
+func TestKeepRawResponse(t *testing.T) {
+	body := `{"page": {"size": 20}, "unmodeled": true}`
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	})
+	rs, err := dc.SearchEvents(QueryParams{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rs.Raw != nil {
+		t.Errorf("Expected no raw body by default, got: %s", rs.Raw)
+	}
+
+	dc.KeepRawResponse = true
+	rs, err = dc.SearchEvents(QueryParams{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(rs.Raw) != body {
+		t.Errorf("Expected %v, got: %s", body, rs.Raw)
+	}
+	if rs.Page.Size != 20 {
+		t.Errorf("Expected %v, got: %v", 20, rs.Page.Size)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	tests := map[string]struct {
+		status int
+		body   string
+		check  func(*APIError) bool
+	}{
+		"fault": {
+			http.StatusUnauthorized,
+			`{"fault": {"faultstring": "Invalid ApiKey", "detail": {"errorcode": "oauth.v2.InvalidApiKey"}}}`,
+			func(e *APIError) bool {
+				return e.Fault != nil &&
+					e.Fault.FaultString == "Invalid ApiKey" &&
+					e.Fault.Detail.ErrorCode == "oauth.v2.InvalidApiKey" &&
+					e.Code == "oauth.v2.InvalidApiKey" &&
+					e.Message == "Invalid ApiKey" &&
+					errors.Is(e, ErrInvalidApiKey) &&
+					strings.Contains(e.Error(), "Invalid ApiKey")
+			},
+		},
+		"errors": {
+			http.StatusNotFound,
+			`{"errors": [{"code": "DIS1004", "detail": "Resource not found with provided criteria", "status": "404"}]}`,
+			func(e *APIError) bool {
+				return len(e.Errors) == 1 && e.Errors[0].Code == "DIS1004" &&
+					e.Code == "DIS1004" &&
+					e.Message == "Resource not found with provided criteria" &&
+					!errors.Is(e, ErrInvalidApiKey)
+			},
+		},
+		"not json": {
+			http.StatusBadGateway,
+			`<html>Bad Gateway</html>`,
+			func(e *APIError) bool {
+				return e.Fault == nil && e.Errors == nil && e.Code == ""
+			},
+		},
+	}
+	for name, tt := range tests {
+		dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			fmt.Fprint(w, tt.body)
+		})
+		_, err := dc.SearchEvents(QueryParams{})
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Errorf("%s: Expected an APIError, got: %v", name, err)
+			continue
+		}
+		if apiErr.StatusCode != tt.status {
+			t.Errorf(
+				"%s: Expected %v, got: %v",
+				name,
+				tt.status,
+				apiErr.StatusCode,
+			)
+		}
+		if apiErr.Body != tt.body {
+			t.Errorf("%s: Expected %v, got: %v", name, tt.body, apiErr.Body)
+		}
+		if !tt.check(apiErr) {
+			t.Errorf("%s: Unexpected APIError: %+v", name, apiErr)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/suggest" {
+			t.Errorf("Expected /suggest, got: %v", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("keyword") != "radio" {
+			t.Errorf("Expected %v, got: %v", "radio", q.Get("keyword"))
+		}
+		if fmt.Sprint(q["resource"]) != "[attractions venues]" {
+			t.Errorf(
+				"Expected %v, got: %v",
+				"[attractions venues]",
+				q["resource"],
+			)
+		}
+		fmt.Fprintf(
+			w,
+			`{"_embedded": {"attractions": [%s], "venues": [%s]}}`,
+			testAttractionJson,
+			testVenueJson,
+		)
+	})
+	rs, err := dc.Suggest("radio", QueryParams{
+		Resource: []string{ResourceAttractions, ResourceVenues},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rs.Embedded.Attractions) != 1 || len(rs.Embedded.Venues) != 1 {
+		t.Errorf("Expected one attraction and venue, got: %+v", rs.Embedded)
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	var userAgent string
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.UserAgent()
+		fmt.Fprint(w, `{"page": {"size": 20}}`)
+	})
+	if _, err := dc.SearchEvents(QueryParams{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if userAgent != DefaultUserAgent {
+		t.Errorf("Expected %v, got: %v", DefaultUserAgent, userAgent)
+	}
+
+	dc.UserAgent = "myapp/1.0"
+	if _, err := dc.SearchEvents(QueryParams{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if userAgent != "myapp/1.0" {
+		t.Errorf("Expected %v, got: %v", "myapp/1.0", userAgent)
+	}
+}
+
+// closeRecorder records whether a body was closed
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestGzipResponse(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			t.Errorf(
+				"Expected %v, got: %v",
+				"gzip",
+				r.Header.Get("Accept-Encoding"),
+			)
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		fmt.Fprint(gz, `{"_embedded": {"events": [{"id": "1"}]}, "page": {"size": 20}}`)
+		gz.Close()
+	})
+	rs, err := dc.SearchEvents(QueryParams{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rs.Embedded.Events) != 1 {
+		t.Errorf("Expected %v, got: %v", 1, len(rs.Embedded.Events))
+	}
+}
+
+func TestGzipBodyClose(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	fmt.Fprint(gz, `{}`)
+	gz.Close()
+	body := &closeRecorder{Reader: &buf}
+	resp := &http.Response{
+		Header: http.Header{"Content-Encoding": {"gzip"}},
+		Body:   body,
+	}
+	resp, err := decompress(resp)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.Header.Get("Content-Encoding") != "" {
+		t.Errorf("Expected Content-Encoding to be removed")
+	}
+	decoded, _ := io.ReadAll(resp.Body)
+	if string(decoded) != "{}" {
+		t.Errorf("Expected %v, got: %s", "{}", decoded)
+	}
+	resp.Body.Close()
+	if !body.closed {
+		t.Errorf("Expected the underlying body to be closed")
+	}
+}
+
+func TestRedactURLWithoutApiKey(t *testing.T) {
+	u, _ := url.Parse(DiscoveryApiUrl + "/events?keyword=radiohead")
+	expectedUrl := DiscoveryApiUrl + "/events?keyword=radiohead"
+	if got := RedactURL(*u); got != expectedUrl {
+		t.Errorf("Expected %v, got: %v", expectedUrl, got)
+	}
+	if u.String() != expectedUrl {
+		t.Errorf("Expected the given URL to be unmodified, got: %v", u)
+	}
+}
+
+func TestDefaultLocale(t *testing.T) {
+	var locale string
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		locale = r.URL.Query().Get("locale")
+		fmt.Fprint(w, `{"page": {"size": 20}}`)
+	})
+	dc.DefaultLocale = "en-us"
+	if _, err := dc.SearchEvents(QueryParams{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if locale != "en-us" {
+		t.Errorf("Expected %v, got: %v", "en-us", locale)
+	}
+	if _, err := dc.SearchEvents(QueryParams{Locale: "fr-ca"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if locale != "fr-ca" {
+		t.Errorf("Expected %v, got: %v", "fr-ca", locale)
+	}
+}
+
+func TestWithBaseURL(t *testing.T) {
+	valid := map[string]string{
+		"http://localhost:8080":                "http://localhost:8080",
+		"http://localhost:8080/":               "http://localhost:8080",
+		"https://example.com/discovery/v2//":   "https://example.com/discovery/v2",
+		"https://example.com/tm/discovery/v2/": "https://example.com/tm/discovery/v2",
+	}
+	for baseUrl, expected := range valid {
+		dc, err := NewDiscoveryClient("12345", WithBaseURL(baseUrl))
+		if err != nil {
+			t.Errorf("%s: Unexpected error: %v", baseUrl, err)
+			continue
+		}
+		if dc.ApiUrl.String() != expected {
+			t.Errorf("Expected %v, got: %v", expected, dc.ApiUrl.String())
+		}
+	}
+	invalid := []string{
+		"localhost:8080",
+		"/discovery/v2",
+		"ftp://example.com",
+		"http://",
+		"http://example.com?apikey=12345",
+	}
+	for _, baseUrl := range invalid {
+		if _, err := NewDiscoveryClient("12345", WithBaseURL(baseUrl)); err == nil {
+			t.Errorf("%s: Expected an error", baseUrl)
+		}
+	}
+}
+
+func TestEventsIteratorContextCancelled(t *testing.T) {
+	dc := newPagedTestClient(t, 5)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pages := 0
+	it := dc.EventsIteratorContext(ctx, QueryParams{Size: 1})
+	for it.Next() {
+		pages++
+		if pages == 2 {
+			cancel()
+		}
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("Expected %v, got: %v", context.Canceled, it.Err())
+	}
+	if pages != 2 {
+		t.Errorf("Expected %v, got: %v", 2, pages)
+	}
+}
+
+func TestNextPageContextMaxDepthBeforeRequest(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request, got: %v", r.URL)
+	})
+	page := &PagedResponse{
+		Links: Links{Next: Link{Href: "/events?page=6&size=200"}},
+		Page:  Page{Size: 200, Number: 5},
+	}
+	_, err := page.NextPageContext(context.Background(), dc)
+	if !errors.Is(err, ErrMaxPageDepth) {
+		t.Errorf("Expected %v, got: %v", ErrMaxPageDepth, err)
+	}
+}
+
+func TestCache(t *testing.T) {
+	requests := 0
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, testEventJson)
+	})
+	dc.Cache = NewMemoryCache()
+	for i := 0; i < 3; i++ {
+		event, err := dc.GetEventTyped("G5diZfkn0B-bh")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if event.ID != "G5diZfkn0B-bh" {
+			t.Errorf("Expected %v, got: %v", "G5diZfkn0B-bh", event.ID)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expected %v, got: %v", 1, requests)
+	}
+	if _, err := dc.GetEventTyped("other"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected %v, got: %v", 2, requests)
+	}
+}
+
+func TestCacheNoStore(t *testing.T) {
+	requests := 0
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Cache-Control", "no-store")
+		fmt.Fprint(w, testEventJson)
+	})
+	dc.Cache = NewMemoryCache()
+	for i := 0; i < 2; i++ {
+		if _, err := dc.GetEventTyped("G5diZfkn0B-bh"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Expected %v, got: %v", 2, requests)
+	}
+}
+
+func TestCacheSkipsErrors(t *testing.T) {
+	requests := 0
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	})
+	dc.Cache = NewMemoryCache()
+	for i := 0; i < 2; i++ {
+		if _, err := dc.GetEventTyped("G5diZfkn0B-bh"); err == nil {
+			t.Errorf("Expected error, got: %v", err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Expected %v, got: %v", 2, requests)
+	}
+}
+
+func TestMemoryCacheExpiry(t *testing.T) {
+	cache := NewMemoryCache()
+	cache.Set("a", []byte("1"), time.Hour)
+	cache.Set("b", []byte("2"), -time.Second)
+	if value, ok := cache.Get("a"); !ok || string(value) != "1" {
+		t.Errorf("Expected %v, got: %v (%v)", "1", string(value), ok)
+	}
+	if _, ok := cache.Get("b"); ok {
+		t.Errorf("Expected expired entry to be missing")
+	}
+}
+
+func TestMemoryCacheSweep(t *testing.T) {
+	cache := NewMemoryCache()
+	cache.Set("a", []byte("1"), -time.Second)
+	cache.Set("b", []byte("2"), -time.Second)
+	if cache.Len() != 2 {
+		t.Fatalf("Expected %v, got: %v", 2, cache.Len())
+	}
+	// Due for a sweep
+	cache.nextSweep = time.Time{}
+	cache.Set("c", []byte("3"), time.Hour)
+	if cache.Len() != 1 {
+		t.Errorf("Expected %v, got: %v", 1, cache.Len())
+	}
+}
+
+func TestMemoryCacheMaxEntries(t *testing.T) {
+	cache := NewMemoryCache()
+	cache.MaxEntries = 2
+	cache.Set("a", []byte("1"), time.Minute)
+	cache.Set("b", []byte("2"), time.Hour)
+	cache.Set("a", []byte("3"), time.Minute)
+	cache.Set("c", []byte("4"), time.Hour)
+	if cache.Len() != 2 {
+		t.Errorf("Expected %v, got: %v", 2, cache.Len())
+	}
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("Expected the entry closest to expiring to be evicted")
+	}
+	for _, key := range []string{"b", "c"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("Expected %v to be cached", key)
+		}
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	tests := []struct {
+		header   http.Header
+		fallback time.Duration
+		expected time.Duration
+	}{
+		{http.Header{}, 0, DefaultCacheTTL},
+		{http.Header{}, time.Hour, time.Hour},
+		{http.Header{"Cache-Control": {"public, max-age=30"}}, 0, 30 * time.Second},
+		{http.Header{"Cache-Control": {"no-cache"}}, time.Hour, 0},
+		{http.Header{"Expires": {"0"}}, time.Hour, 0},
+	}
+	for _, test := range tests {
+		if ttl := cacheTTL(test.header, test.fallback); ttl != test.expected {
+			t.Errorf("Expected %v, got: %v (%v)", test.expected, ttl, test.header)
+		}
+	}
+}
+
+func TestClientNextPage(t *testing.T) {
+	dc := newPagedTestClient(t, 2)
+	first, err := dc.SearchEvents(QueryParams{Size: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	next, err := dc.NextPage(first)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if next.Page.Number != 1 {
+		t.Errorf("Expected %v, got: %v", 1, next.Page.Number)
+	}
+	last, err := dc.NextPage(next)
+	if err != nil || last != nil {
+		t.Errorf("Expected no page or error, got: %v, %v", last, err)
+	}
+	prev, err := dc.PreviousPage(first)
+	if err != nil || prev != nil {
+		t.Errorf("Expected no page or error, got: %v, %v", prev, err)
+	}
+}
+
+func TestEscapeKeywords(t *testing.T) {
+	var keyword string
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		keyword = r.URL.Query().Get("keyword")
+		fmt.Fprint(w, `{"page": {}}`)
+	})
+	dc.EscapeKeywords = true
+	_, err := dc.SearchEvents(QueryParams{Keyword: "Simon & Garfunkel"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if keyword != `Simon \& Garfunkel` {
+		t.Errorf("Expected %v, got: %v", `Simon \& Garfunkel`, keyword)
+	}
+}
+
+func TestEscapeKeywordsPaged(t *testing.T) {
+	var keywords []string
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			keywords = append(keywords, query.Get("keyword"))
+			// Links echo the keyword as the API received it
+			next := url.Values{"keyword": {query.Get("keyword")}, "page": {"1"}}
+			self := url.Values{
+				"keyword": {query.Get("keyword")},
+				"page":    {query.Get("page")},
+			}
+			fmt.Fprintf(
+				w,
+				`{"_links": {"self": {"href": "/events?%s"}, "next": {"href": "/events?%s"}}, "page": {"size": 1, "totalElements": 2, "totalPages": 2}}`,
+				self.Encode(),
+				next.Encode(),
+			)
+		},
+	))
+	t.Cleanup(server.Close)
+	dc, err := NewDiscoveryClient(
+		"12345",
+		WithBaseURL(server.URL),
+		WithKeywordEscaping(),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	first, err := dc.SearchEvents(QueryParams{Keyword: "Simon & Garfunkel"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := first.NextPage(dc); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := first.GoToPage(dc, 1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{
+		`Simon \& Garfunkel`,
+		`Simon \& Garfunkel`,
+		`Simon \& Garfunkel`,
+	}
+	if !reflect.DeepEqual(keywords, expected) {
+		t.Errorf("Expected %v, got: %v", expected, keywords)
+	}
+}
+
+func TestAllEventsConcurrent(t *testing.T) {
+	dc := newPagedTestClient(t, 7)
+	dc.Concurrency = 3
+	events, err := dc.AllEvents(QueryParams{Size: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(events) != 7 {
+		t.Fatalf("Expected %v, got: %v", 7, len(events))
+	}
+	for i, event := range events {
+		if event["id"] != fmt.Sprint(i) {
+			t.Errorf("Expected %v, got: %v", i, event["id"])
+		}
+	}
+}
+
+func TestAllEventsConcurrentMaxPageDepth(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page == "" {
+			page = "0"
+		}
+		mu.Lock()
+		requested = append(requested, page)
+		mu.Unlock()
+		fmt.Fprintf(
+			w,
+			`{"_embedded": {"events": [{"id": "%s"}]}, "page": {"size": 200, "totalElements": 2000, "totalPages": 10, "number": %s}}`,
+			page,
+			page,
+		)
+	})
+	dc.Concurrency = 4
+	events, err := dc.AllEvents(QueryParams{Size: 200})
+	if !errors.Is(err, ErrMaxPageDepth) {
+		t.Errorf("Expected %v, got: %v", ErrMaxPageDepth, err)
+	}
+	// Pages 0-4 hold results 0-999, page 5 would start past the limit
+	if len(events) != 5 {
+		t.Errorf("Expected %v, got: %v", 5, len(events))
+	}
+	if len(requested) != 5 {
+		t.Errorf("Expected %v, got: %v", 5, requested)
+	}
+}
+
+func TestAllEventsConcurrentError(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page == "2" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(
+			w,
+			`{"_embedded": {"events": [{"id": "%s"}]}, "page": {"size": 1, "totalElements": 5, "totalPages": 5, "number": 0}}`,
+			page,
+		)
+	})
+	dc.Concurrency = 2
+	events, err := dc.AllEvents(QueryParams{Size: 1})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError, got: %v", err)
+	}
+	if events != nil {
+		t.Errorf("Expected no events, got: %v", events)
+	}
+}
+
+func TestDefaultTransport(t *testing.T) {
+	dc, err := NewDiscoveryClient("12345")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if dc.HTTPClient == nil || dc.HTTPClient.Transport != defaultTransport {
+		t.Errorf("Expected the default transport, got: %v", dc.HTTPClient)
+	}
+	if defaultTransport.MaxIdleConnsPerHost != DefaultMaxIdleConnsPerHost {
+		t.Errorf(
+			"Expected %v, got: %v",
+			DefaultMaxIdleConnsPerHost,
+			defaultTransport.MaxIdleConnsPerHost,
+		)
+	}
+
+	httpClient := &http.Client{}
+	dc, err = NewDiscoveryClient("12345", WithHTTPClient(httpClient))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if dc.HTTPClient != httpClient {
+		t.Errorf("Expected %v, got: %v", httpClient, dc.HTTPClient)
+	}
+}
+
+func TestWithTransport(t *testing.T) {
+	transport := NewTransport()
+	transport.MaxIdleConnsPerHost = 64
+	httpClient := &http.Client{Timeout: time.Minute}
+	dc, err := NewDiscoveryClient(
+		"12345",
+		WithTransport(transport),
+		WithHTTPClient(httpClient),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if dc.HTTPClient.Transport != transport {
+		t.Errorf("Expected %v, got: %v", transport, dc.HTTPClient.Transport)
+	}
+	if dc.HTTPClient.Timeout != time.Minute {
+		t.Errorf("Expected %v, got: %v", time.Minute, dc.HTTPClient.Timeout)
+	}
+	if httpClient.Transport != nil {
+		t.Errorf("Expected the given client to be unmodified")
+	}
+
+	if _, err := NewDiscoveryClient("12345", WithTransport(nil)); err == nil {
+		t.Errorf("Expected error for nil transport")
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	httpClient := &http.Client{Timeout: time.Minute}
+	dc, err := NewDiscoveryClient(
+		"12345",
+		WithTimeout(5*time.Second),
+		WithHTTPClient(httpClient),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if dc.HTTPClient.Timeout != 5*time.Second {
+		t.Errorf("Expected %v, got: %v", 5*time.Second, dc.HTTPClient.Timeout)
+	}
+	if httpClient.Timeout != time.Minute {
+		t.Errorf("Expected %v, got: %v", time.Minute, httpClient.Timeout)
+	}
+
+	dc, err = NewDiscoveryClient("12345", WithTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if dc.HTTPClient == nil || dc.HTTPClient.Timeout != time.Second {
+		t.Errorf("Expected %v timeout, got: %v", time.Second, dc.HTTPClient)
+	}
+
+	if _, err := NewDiscoveryClient("12345", WithTimeout(0)); err == nil {
+		t.Errorf("Expected error for zero timeout")
+	}
+}
+
+func TestDo(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/events/G5diZfkn0B-bh/images" {
+			t.Errorf("Expected %v, got: %v", "/events/G5diZfkn0B-bh/images", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("apikey") != "12345" {
+			t.Errorf("Expected %v, got: %v", "12345", q.Get("apikey"))
+		}
+		if q.Get("foo") != "bar" {
+			t.Errorf("Expected %v, got: %v", "bar", q.Get("foo"))
+		}
+		if r.Header.Get("User-Agent") != DefaultUserAgent {
+			t.Errorf("Expected %v, got: %v", DefaultUserAgent, r.Header.Get("User-Agent"))
+		}
+		w.WriteHeader(http.StatusTeapot)
+		fmt.Fprint(w, `{"images": []}`)
+	})
+	resp, err := dc.Do(
+		context.Background(),
+		"events/G5diZfkn0B-bh/images",
+		url.Values{"foo": {"bar"}},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusTeapot {
+		t.Errorf("Expected %v, got: %v", http.StatusTeapot, resp.StatusCode)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != `{"images": []}` {
+		t.Errorf("Expected %v, got: %v", `{"images": []}`, string(body))
+	}
+}
+
+func TestAPIErrorFromAllMethods(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"fault": {"faultstring": "Invalid ApiKey"}}`)
+	})
+	page := &PagedResponse{
+		Links: Links{
+			Next: Link{Href: "/events?page=1"},
+			Prev: Link{Href: "/events?page=0"},
+		},
+	}
+	calls := map[string]func() error{
+		"GetEvent": func() error {
+			_, err := dc.GetEvent("G5diZfkn0B-bh")
+			return err
+		},
+		"SearchEvents": func() error {
+			_, err := dc.SearchEvents(QueryParams{})
+			return err
+		},
+		"NextPage": func() error {
+			_, err := page.NextPage(dc)
+			return err
+		},
+		"PreviousPage": func() error {
+			_, err := page.PreviousPage(dc)
+			return err
+		},
+	}
+	for name, call := range calls {
+		var apiErr *APIError
+		if err := call(); !errors.As(err, &apiErr) {
+			t.Errorf("%s: Expected *APIError, got: %v", name, err)
+			continue
+		}
+		if apiErr.StatusCode != http.StatusUnauthorized {
+			t.Errorf("%s: Expected %v, got: %v", name, http.StatusUnauthorized, apiErr.StatusCode)
+		}
+	}
+}
+
+func TestDisableAPIKey(t *testing.T) {
+	var requests []*http.Request
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		fmt.Fprint(w, `{"page": {}}`)
+	})
+	dc.DisableAPIKey = true
+	if _, err := dc.SearchEvents(QueryParams{Keyword: "foo"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := dc.GetVenue("KovZpZA7AAEA"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	dc.AuthMode = AuthHeader
+	if _, err := dc.SearchVenues(QueryParams{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, r := range requests {
+		if r.URL.Query().Has("apikey") {
+			t.Errorf("Expected no apikey param, got: %v", r.URL)
+		}
+		if r.Header.Get(ApiKeyHeader) != "" {
+			t.Errorf("Expected no apikey header, got: %v", r.Header)
+		}
+	}
+	if u := dc.EventsUrl(); u.Query().Has("apikey") {
+		t.Errorf("Expected no apikey param, got: %v", u.String())
+	}
+}
+
+func TestWithoutAPIKey(t *testing.T) {
+	dc, err := NewDiscoveryClient("", WithoutAPIKey())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !dc.DisableAPIKey {
+		t.Errorf("Expected DisableAPIKey to be set")
+	}
+}
+
+func TestSearchEventsEmptyApiKey(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("apikey") {
+			t.Errorf("Expected no apikey param, got: %v", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"page": {}}`)
+	})
+	dc.ApiKey = ""
+	if _, err := dc.SearchEvents(QueryParams{Keyword: "foo"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestCountEvents(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("size") != "1" {
+			t.Errorf("Expected %v, got: %v", "1", q.Get("size"))
+		}
+		if q.Has("page") {
+			t.Errorf("Expected no page, got: %v", q.Get("page"))
+		}
+		fmt.Fprint(w, `{"_embedded": {"events": [{"id": "1"}]}, "page": {"size": 1, "totalElements": 1234, "totalPages": 1234, "number": 0}}`)
+	})
+	count, err := dc.CountEvents(QueryParams{Keyword: "foo", Size: 50, Page: 3})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if count != 1234 {
+		t.Errorf("Expected %v, got: %v", 1234, count)
+	}
+}
+
+func TestGoToPage(t *testing.T) {
+	dc := newPagedTestClient(t, 5)
+	first, err := dc.SearchEvents(QueryParams{Size: 1, Keyword: "foo"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	page, err := dc.GoToPage(first, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if page.Page.Number != 3 {
+		t.Errorf("Expected %v, got: %v", 3, page.Page.Number)
+	}
+	if _, err := first.GoToPage(dc, 5); !errors.Is(err, ErrPageOutOfRange) {
+		t.Errorf("Expected %v, got: %v", ErrPageOutOfRange, err)
+	}
+	if _, err := first.GoToPage(dc, -1); !errors.Is(err, ErrPageOutOfRange) {
+		t.Errorf("Expected %v, got: %v", ErrPageOutOfRange, err)
+	}
+}
+
+func TestGoToPageTemplatedSelfLink(t *testing.T) {
+	var query url.Values
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		fmt.Fprint(w, `{"page": {"size": 20, "totalPages": 10, "number": 4}}`)
+	})
+	page := &PagedResponse{
+		Links: Links{Self: Link{
+			Href:      "/events?keyword=foo&size=20{&page,sort}",
+			Templated: true,
+		}},
+		Page: Page{Size: 20, TotalPages: 10},
+	}
+	if _, err := page.GoToPage(dc, 4); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if query.Get("page") != "4" || query.Get("keyword") != "foo" {
+		t.Errorf("Expected page 4 of keyword foo, got: %v", query)
+	}
+}
+
+func TestGoToPageErrors(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request, got: %v", r.URL)
+	})
+	page := &PagedResponse{
+		Links: Links{Self: Link{Href: "/events?size=200"}},
+		Page:  Page{Size: 200, TotalPages: 25},
+	}
+	if _, err := page.GoToPage(dc, 5); !errors.Is(err, ErrMaxPageDepth) {
+		t.Errorf("Expected %v, got: %v", ErrMaxPageDepth, err)
+	}
+	page.Links.Self.Href = ""
+	if _, err := page.GoToPage(dc, 1); !errors.Is(err, ErrNoSelfLink) {
+		t.Errorf("Expected %v, got: %v", ErrNoSelfLink, err)
+	}
+}
+
+func TestPagedResponseParams(t *testing.T) {
+	dc := newPagedTestClient(t, 3)
+	queryParams := QueryParams{Size: 1, Keyword: "foo", CountryCode: "US"}
+	first, err := dc.SearchEvents(queryParams)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	params, ok := first.Params()
+	if !ok || !reflect.DeepEqual(params, queryParams) {
+		t.Errorf("Expected %+v, got: %+v (%v)", queryParams, params, ok)
+	}
+
+	next, err := first.NextPage(dc)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	params, ok = next.Params()
+	queryParams.Page = 1
+	if !ok || !reflect.DeepEqual(params, queryParams) {
+		t.Errorf("Expected %+v, got: %+v (%v)", queryParams, params, ok)
+	}
+
+	if _, ok := (&PagedResponse{}).Params(); ok {
+		t.Errorf("Expected no params for a decoded response")
+	}
+}
+
+func TestLoggerSingleStructuredLine(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "G5diZfkn0B-bh"}`)
+	})
+	var buf bytes.Buffer
+	dc.Logger = slog.New(
+		slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+	if _, err := dc.GetEvent("G5diZfkn0B-bh"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected %v, got: %v", 1, lines)
+	}
+	var record map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if record["method"] != http.MethodGet {
+		t.Errorf("Expected %v, got: %v", http.MethodGet, record["method"])
+	}
+	if record["status"] != float64(http.StatusOK) {
+		t.Errorf("Expected %v, got: %v", http.StatusOK, record["status"])
+	}
+	if !strings.Contains(fmt.Sprint(record["url"]), "apikey=REDACTED") {
+		t.Errorf("Expected redacted url, got: %v", record["url"])
+	}
+	if _, ok := record["duration"]; !ok {
+		t.Errorf("Expected duration to be logged, got: %v", record)
+	}
+}
+
+func TestRedirectKeepsApiKey(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/events/old" {
+			http.Redirect(w, r, "/events/new", http.StatusMovedPermanently)
+			return
+		}
+		if r.URL.Query().Get("apikey") != "12345" {
+			t.Errorf("Expected %v, got: %v", "12345", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, testEventJson)
+	})
+	if _, err := dc.GetEvent("old"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestRedirectOtherHostDropsApiKeyHeader(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get(ApiKeyHeader) != "" {
+				t.Errorf("Expected no apikey header, got: %v", r.Header)
+			}
+			fmt.Fprint(w, testEventJson)
+		},
+	))
+	t.Cleanup(other.Close)
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, other.URL+"/events/new", http.StatusFound)
+	})
+	dc.AuthMode = AuthHeader
+	if _, err := dc.GetEvent("old"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestRedirectSchemeDowngradeDropsApiKey(t *testing.T) {
+	for _, mode := range []AuthMode{AuthQuery, AuthHeader} {
+		dc := &DiscoveryClient{ApiKey: "12345", AuthMode: mode}
+		from, _ := http.NewRequest(
+			http.MethodGet,
+			"https://example.com/events/old?apikey=12345",
+			nil,
+		)
+		to, _ := http.NewRequest(
+			http.MethodGet,
+			"http://example.com/events/new?apikey=12345",
+			nil,
+		)
+		to.Header.Set(ApiKeyHeader, "12345")
+		if err := dc.checkRedirect(nil)(to, []*http.Request{from}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if to.URL.Query().Has("apikey") || to.Header.Get(ApiKeyHeader) != "" {
+			t.Errorf("%v: Expected no API key, got: %v %v", mode, to.URL, to.Header)
+		}
+	}
+}
+
+func TestDisableRedirects(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/events/old" {
+			http.Redirect(w, r, "/events/new", http.StatusMovedPermanently)
+			return
+		}
+		t.Errorf("Expected redirect not to be followed, got: %v", r.URL)
+	})
+	dc.DisableRedirects = true
+	_, err := dc.GetEvent("old")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusMovedPermanently {
+		t.Errorf("Expected %v, got: %v", http.StatusMovedPermanently, err)
+	}
+}
+
+func TestNearbyEvents(t *testing.T) {
+	var query url.Values
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		fmt.Fprint(w, `{"page": {}}`)
+	})
+	_, err := dc.NearbyEvents(40.75, -73.99, 10, QueryParams{Keyword: "jazz"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		"latlong": "40.75,-73.99",
+		"radius":  "10",
+		"unit":    "miles",
+		"keyword": "jazz",
+	}
+	for key, value := range expected {
+		if query.Get(key) != value {
+			t.Errorf("Expected %v=%v, got: %v", key, value, query.Get(key))
+		}
+	}
+}
+
+func TestStreamEvents(t *testing.T) {
+	dc := newPagedTestClient(t, 4)
+	events, errs := dc.StreamEvents(context.Background(), QueryParams{Size: 1})
+	var ids []any
+	for event := range events {
+		ids = append(ids, event["id"])
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fmt.Sprint(ids) != "[0 1 2 3]" {
+		t.Errorf("Expected %v, got: %v", "[0 1 2 3]", ids)
+	}
+}
+
+func TestStreamEventsCancelled(t *testing.T) {
+	dc := newPagedTestClient(t, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, errs := dc.StreamEvents(ctx, QueryParams{Size: 1})
+	received := 0
+	for range events {
+		received++
+		if received == 2 {
+			cancel()
+		}
+	}
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected %v, got: %v", context.Canceled, err)
+	}
+	if received > 3 {
+		t.Errorf("Expected streaming to stop after cancelling, got: %v", received)
+	}
+}
+
+func TestStreamEventsError(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	events, errs := dc.StreamEvents(context.Background(), QueryParams{})
+	for range events {
+		t.Errorf("Expected no events")
+	}
+	var apiErr *APIError
+	if err := <-errs; !errors.As(err, &apiErr) {
+		t.Errorf("Expected *APIError, got: %v", err)
+	}
+}
+
+func TestETags(t *testing.T) {
+	requests := 0
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		if requests > 1 {
+			t.Errorf("Expected If-None-Match, got: %v", r.Header)
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, testEventJson)
+	})
+	dc.UseETags = true
+	if _, err := dc.GetEventTyped("G5diZfkn0B-bh"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	event, err := dc.GetEventTyped("G5diZfkn0B-bh")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if event.Name != "Radiohead" {
+		t.Errorf("Expected %v, got: %v", "Radiohead", event.Name)
+	}
+	if requests != 2 {
+		t.Errorf("Expected %v, got: %v", 2, requests)
+	}
+}
+
+// expiringCache is a Cache whose entries expire immediately
+type expiringCache struct{}
+
+func (expiringCache) Get(string) ([]byte, bool)         { return nil, false }
+func (expiringCache) Set(string, []byte, time.Duration) {}
+
+func TestETagsWithExpiredCache(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"_embedded": {"events": [{"id": "1"}]}, "page": {"size": 20, "totalElements": 1, "totalPages": 1}}`)
+	})
+	dc.Cache = expiringCache{}
+	dc.UseETags = true
+	for i := 0; i < 3; i++ {
+		rs, err := dc.SearchEvents(QueryParams{})
+		if err != nil {
+			t.Fatalf("%d: Unexpected error: %v", i, err)
+		}
+		if len(rs.Embedded.Events) != 1 {
+			t.Errorf("%d: Expected 1 event, got: %v", i, rs.Embedded.Events)
+		}
+		if notModified := i > 0; rs.Meta.NotModified != notModified {
+			t.Errorf("%d: Expected %v, got: %v", i, notModified, rs.Meta.NotModified)
+		}
+	}
+}
+
+func TestETagsNotModifiedWithoutBody(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	})
+	dc.UseETags = true
+	_, err := dc.SearchEvents(QueryParams{})
+	if !errors.Is(err, ErrNotModified) {
+		t.Errorf("Expected %v, got: %v", ErrNotModified, err)
+	}
+}
+
+func TestETagsEviction(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"`+r.URL.Query().Get("keyword")+`"`)
+		fmt.Fprint(w, `{"page": {}}`)
+	})
+	dc.UseETags = true
+	for i := 0; i <= maxETags; i++ {
+		if _, err := dc.SearchEvents(QueryParams{Keyword: fmt.Sprint(i)}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if len(dc.etags) != maxETags {
+		t.Errorf("Expected %v, got: %v", maxETags, len(dc.etags))
+	}
+}
+
+func TestETagsDisabled(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			t.Errorf("Expected no If-None-Match, got: %v", r.Header)
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, testEventJson)
+	})
+	for i := 0; i < 2; i++ {
+		if _, err := dc.GetEventTyped("G5diZfkn0B-bh"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+}
+
+func TestClassificationTree(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+  "_embedded": {
+    "classifications": [{
+      "family": false,
+      "segment": {
+        "id": "KZFzniwnSyZfZ7v7nJ",
+        "name": "Music",
+        "_embedded": {
+          "genres": [{
+            "id": "KnvZfZ7vAeA",
+            "name": "Rock",
+            "_embedded": {
+              "subgenres": [
+                {"id": "KZazBEonSMnZfZ7v6F1", "name": "Pop"},
+                {"id": "KZazBEonSMnZfZ7vF17", "name": "Alternative Rock"}
+              ]
+            }
+          }]
+        }
+      }
+    }]
+  },
+  "page": {"size": 20, "totalElements": 1, "totalPages": 1, "number": 0}
+}`)
+	})
+	rs, err := dc.SearchClassifications(QueryParams{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	classifications, err := rs.ClassificationTree()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(classifications) != 1 {
+		t.Fatalf("Expected %v, got: %v", 1, len(classifications))
+	}
+	segment := classifications[0].Segment
+	if segment.Name != "Music" || len(segment.Genres) != 1 {
+		t.Fatalf("Expected Music with one genre, got: %+v", segment)
+	}
+	genre := segment.Genres[0]
+	if genre.Name != "Rock" || len(genre.SubGenres) != 2 {
+		t.Fatalf("Expected Rock with two sub-genres, got: %+v", genre)
+	}
+	if genre.SubGenres[1].Name != "Alternative Rock" {
+		t.Errorf("Expected %v, got: %v", "Alternative Rock", genre.SubGenres[1].Name)
+	}
+}
+
+func TestStrictDecoding(t *testing.T) {
+	body := `{"id": "G5diZfkn0B-bh"}`
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	})
+	if _, err := dc.GetEventTyped("G5diZfkn0B-bh"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	body = `{"id": "G5diZfkn0B-bh"}{"id": "G5diZfkn0B-bh"}`
+	if _, err := dc.GetEventTyped("G5diZfkn0B-bh"); err != nil {
+		t.Errorf("Expected lenient decoding by default, got: %v", err)
+	}
+	dc.StrictDecoding = true
+	_, err := dc.GetEventTyped("G5diZfkn0B-bh")
+	if !errors.Is(err, ErrTrailingData) {
+		t.Errorf("Expected %v, got: %v", ErrTrailingData, err)
+	}
+	body = `{"id": "G5diZfkn0B-bh"`
+	if _, err := dc.GetEventTyped("G5diZfkn0B-bh"); err == nil {
+		t.Errorf("Expected an error for a truncated body")
+	}
+}
+
+func TestKeepRawResponseTrailingData(t *testing.T) {
+	body := `{"page": {"size": 20}}{"page": {"size": 20}}`
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	})
+	dc.KeepRawResponse = true
+	rs, err := dc.SearchEvents(QueryParams{})
+	if err != nil {
+		t.Fatalf("Expected lenient decoding without StrictDecoding, got: %v", err)
+	}
+	if string(rs.Raw) != body {
+		t.Errorf("Expected %v, got: %v", body, string(rs.Raw))
+	}
+	dc.StrictDecoding = true
+	if _, err := dc.SearchEvents(QueryParams{}); !errors.Is(err, ErrTrailingData) {
+		t.Errorf("Expected %v, got: %v", ErrTrailingData, err)
+	}
+}
+
+func TestDisallowUnknownFields(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "G5diZfkn0B-bh", "newField": true}`)
+	})
+	if _, err := dc.GetEventTyped("G5diZfkn0B-bh"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	dc.DisallowUnknownFields = true
+	if _, err := dc.GetEventTyped("G5diZfkn0B-bh"); err == nil {
+		t.Errorf("Expected an error for an unknown field")
+	}
+}
+
+func TestResponseMeta(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "abc-123")
+		w.Header().Set("Rate-Limit-Available", "4999")
+		fmt.Fprint(w, `{"page": {"totalElements": 0}}`)
+	})
+	rs, err := dc.SearchEvents(QueryParams{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rs.Meta == nil {
+		t.Fatalf("Expected response meta")
+	}
+	if rs.Meta.StatusCode != http.StatusOK {
+		t.Errorf("Expected %v, got: %v", http.StatusOK, rs.Meta.StatusCode)
+	}
+	if rs.Meta.RequestID != "abc-123" {
+		t.Errorf("Expected %v, got: %v", "abc-123", rs.Meta.RequestID)
+	}
+	if rs.Meta.Headers.Get("Rate-Limit-Available") != "4999" {
+		t.Errorf(
+			"Expected %v, got: %v",
+			"4999",
+			rs.Meta.Headers.Get("Rate-Limit-Available"),
+		)
+	}
+}
+
+func TestPing(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/events" || r.URL.Query().Get("size") != "1" {
+			t.Errorf("Expected a single event search, got: %v", r.URL)
+		}
+		if r.URL.Query().Get("apikey") != "12345" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"fault": {"faultstring": "Invalid ApiKey"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"page": {}}`)
+	})
+	if err := dc.Ping(context.Background()); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	dc.ApiKey = "invalid"
+	err := dc.Ping(context.Background())
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected %v, got: %v", ErrUnauthorized, err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Fault == nil {
+		t.Errorf("Expected *APIError with a fault, got: %v", err)
+	}
+}
+
+func TestPingBypassesCache(t *testing.T) {
+	hits := 0
+	unauthorized := false
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if unauthorized {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"fault": {"faultstring": "Invalid ApiKey"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"page": {}}`)
+	})
+	dc.Cache = NewMemoryCache()
+	if err := dc.Ping(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	unauthorized = true
+	if err := dc.Ping(context.Background()); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected %v, got: %v", ErrUnauthorized, err)
+	}
+	if hits != 2 {
+		t.Errorf("Expected %v, got: %v", 2, hits)
+	}
+}
+
+func TestPingBypassesETags(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"abc"`)
+		fmt.Fprint(w, `{"page": {}}`)
+	})
+	dc.UseETags = true
+	for i := 0; i < 2; i++ {
+		if err := dc.Ping(context.Background()); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+}
+
+func TestPingUnreachable(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {})
+	dc.ApiUrl.Host = "127.0.0.1:1"
+	err := dc.Ping(context.Background())
+	var apiErr *APIError
+	if err == nil || errors.As(err, &apiErr) {
+		t.Errorf("Expected a network error, got: %v", err)
+	}
+}
+
+func TestNextPageMaxResultDepthBoundary(t *testing.T) {
+	requests := 0
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"page": {}}`)
+	})
+	tests := []struct {
+		depth   int
+		size    int
+		number  int
+		allowed bool
+	}{
+		// Page 4 of 200 starts at result 800, page 5 at 1000
+		{0, 200, 3, true},
+		{0, 200, 4, false},
+		// Page 33 of 30 starts at result 990, page 34 at 1020
+		{0, 30, 32, true},
+		{0, 30, 33, false},
+		{0, 1, 998, true},
+		{0, 1, 999, false},
+		{100, 20, 3, true},
+		{100, 20, 4, false},
+	}
+	for _, test := range tests {
+		dc.MaxResultDepth = test.depth
+		page := &PagedResponse{
+			Links: Links{Next: Link{Href: "/events"}},
+			Page:  Page{Size: test.size, Number: test.number},
+		}
+		before := requests
+		_, err := page.NextPage(dc)
+		if test.allowed && err != nil {
+			t.Errorf("%+v: Unexpected error: %v", test, err)
+		}
+		if !test.allowed {
+			if !errors.Is(err, ErrMaxPageDepth) {
+				t.Errorf("%+v: Expected %v, got: %v", test, ErrMaxPageDepth, err)
+			}
+			if requests != before {
+				t.Errorf("%+v: Expected no request past the cap", test)
+			}
+		}
+	}
+}
+
+func TestNextPageMaxResultDepthLastPage(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request, got: %v", r.URL)
+	})
+	// The last page at the cap has no next link, so there's nothing to
+	// truncate
+	page := &PagedResponse{Page: Page{Size: 200, Number: 4}}
+	next, err := page.NextPage(dc)
+	if next != nil || err != nil {
+		t.Errorf("Expected no page or error, got: %v, %v", next, err)
+	}
+}
+
+func TestAllEventsConcurrentMaxResultDepth(t *testing.T) {
+	dc := newPagedTestClient(t, 10)
+	dc.Concurrency = 2
+	dc.MaxResultDepth = 4
+	events, err := dc.AllEvents(QueryParams{Size: 1})
+	if !errors.Is(err, ErrMaxPageDepth) {
+		t.Errorf("Expected %v, got: %v", ErrMaxPageDepth, err)
+	}
+	if len(events) != 4 {
+		t.Errorf("Expected %v, got: %v", 4, len(events))
+	}
+}
+
+func TestErrorTiming(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		w.WriteHeader(http.StatusNotFound)
+	})
+	_, err := dc.GetEvent("G5diZfkn0B-bh")
+	var requestErr *RequestError
+	if errors.As(err, &requestErr) {
+		t.Errorf("Expected no *RequestError by default, got: %v", err)
+	}
+
+	dc.ErrorTiming = true
+	_, err = dc.GetEvent("G5diZfkn0B-bh")
+	if !errors.As(err, &requestErr) {
+		t.Fatalf("Expected *RequestError, got: %v", err)
+	}
+	if requestErr.Duration < 10*time.Millisecond {
+		t.Errorf("Expected at least %v, got: %v", 10*time.Millisecond, requestErr.Duration)
+	}
+	if !strings.Contains(requestErr.URL, "/events/G5diZfkn0B-bh") ||
+		strings.Contains(requestErr.URL, "12345") {
+		t.Errorf("Expected redacted event URL, got: %v", requestErr.URL)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected wrapped *APIError, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "failed after") {
+		t.Errorf("Expected duration in error, got: %v", err)
+	}
+}
+
+func TestGetEvents(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+		time.Sleep(10 * time.Millisecond)
+		id := path.Base(r.URL.Path)
+		if id == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"id": %q, "name": "Event %s"}`, id, id)
+	})
+	dc.Concurrency = 2
+
+	ids := []string{"a", "b", "missing", "c", "d"}
+	events, err := dc.GetEvents(context.Background(), ids)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected *APIError with status 404, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "missing") {
+		t.Errorf("Expected failed ID in error, got: %v", err)
+	}
+	if len(events) != len(ids) {
+		t.Fatalf("Expected %d events, got: %d", len(ids), len(events))
+	}
+	for i, id := range ids {
+		if id == "missing" {
+			if events[i] != nil {
+				t.Errorf("Expected nil event for %s, got: %+v", id, events[i])
+			}
+			continue
+		}
+		if events[i] == nil || events[i].ID != id {
+			t.Errorf("Expected event %s, got: %+v", id, events[i])
+		}
+	}
+	if maxInFlight > 2 {
+		t.Errorf("Expected at most 2 requests at once, got: %d", maxInFlight)
+	}
+}
+
+// testEmptyJson is a search response with no results, with a next link
+// the API shouldn't have included
+const testEmptyJson = `{
+  "_links": {
+    "self": {"href": "/discovery/v2/events?keyword=nothing&page=0&size=20"},
+    "next": {"href": "/discovery/v2/events?keyword=nothing&page=1&size=20"}
+  },
+  "page": {"size": 20, "totalElements": 0, "totalPages": 0, "number": 0}
+}`
+
+func TestEmptyResults(t *testing.T) {
+	for _, body := range []string{testEmptyJson, `{}`} {
+		requests := 0
+		dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			fmt.Fprint(w, body)
+		})
+		rs, err := dc.SearchEvents(QueryParams{Keyword: "nothing"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !rs.Empty() || rs.HasNext() || rs.HasPrev() {
+			t.Errorf("Expected an empty response with no next or previous page")
+		}
+		next, err := rs.NextPage(dc)
+		if next != nil || err != nil {
+			t.Errorf("Expected no next page, got: %v, %v", next, err)
+		}
+		if _, err := rs.GoToPage(dc, 1); !errors.Is(err, ErrPageOutOfRange) {
+			t.Errorf("Expected %v, got: %v", ErrPageOutOfRange, err)
+		}
+
+		it := dc.EventsIterator(QueryParams{Keyword: "nothing"})
+		if it.Next() {
+			t.Errorf("Expected Next to return false")
+		}
+		if err := it.Err(); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		events, err := dc.AllEvents(QueryParams{Keyword: "nothing"})
+		if len(events) != 0 || err != nil {
+			t.Errorf("Expected no events, got: %v, %v", events, err)
+		}
+		if requests != 3 {
+			t.Errorf("Expected %v, got: %v", 3, requests)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	var mu sync.Mutex
+	keywords := map[string]string{}
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		resource := path.Base(r.URL.Path)
+		mu.Lock()
+		keywords[resource] = r.URL.Query().Get("keyword")
+		mu.Unlock()
+		if resource == "venues" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(
+			w,
+			`{"_embedded": {%q: [{"id": "1"}]}, "page": {"size": 20, "totalElements": 1, "totalPages": 1, "number": 0}}`,
+			resource,
+		)
+	})
+	rs, err := dc.Search("radiohead", QueryParams{Keyword: "ignored"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rs.EventsErr != nil || len(rs.Events.Embedded.Events) != 1 {
+		t.Errorf("Expected 1 event, got: %+v, %v", rs.Events, rs.EventsErr)
+	}
+	if rs.AttractionsErr != nil || len(rs.Attractions.Embedded.Attractions) != 1 {
+		t.Errorf(
+			"Expected 1 attraction, got: %+v, %v",
+			rs.Attractions,
+			rs.AttractionsErr,
+		)
+	}
+	var apiErr *APIError
+	if rs.Venues != nil || !errors.As(rs.VenuesErr, &apiErr) {
+		t.Errorf("Expected venues *APIError, got: %+v, %v", rs.Venues, rs.VenuesErr)
+	}
+	if err := rs.Err(); err == nil || !strings.Contains(err.Error(), "venues") {
+		t.Errorf("Expected venues error, got: %v", err)
+	}
+	for _, resource := range []string{"events", "attractions", "venues"} {
+		if keywords[resource] != "radiohead" {
+			t.Errorf("%s: Expected %v, got: %v", resource, "radiohead", keywords[resource])
+		}
+	}
+
+	if _, err := dc.Search("radiohead", QueryParams{Size: -1}); err == nil {
+		t.Errorf("Expected error for invalid query parameters")
+	}
+}
+
+func TestFollowLink(t *testing.T) {
+	var requested *url.URL
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL
+		fmt.Fprint(w, `{"_embedded": {"events": [{"id": "1"}]}, "page": {"size": 20, "totalElements": 1, "totalPages": 1, "number": 0}}`)
+	})
+	rs, err := dc.FollowLink(Link{
+		Href:      "/discovery/v2/events?keyword=foo&page=0{&size,sort}",
+		Templated: true,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rs.Embedded.Events) != 1 {
+		t.Errorf("Expected 1 event, got: %v", rs.Embedded.Events)
+	}
+	// The API's base path is replaced by the test server's (the root)
+	if requested.Path != "/events" {
+		t.Errorf("Expected %v, got: %v", "/events", requested.Path)
+	}
+	q := requested.Query()
+	if q.Get("keyword") != "foo" || q.Get("apikey") != "12345" {
+		t.Errorf("Expected keyword and API key, got: %v", q)
+	}
+	if strings.ContainsAny(requested.RawQuery, "{}") {
+		t.Errorf("Expected template removed, got: %v", requested.RawQuery)
+	}
+}
+
+func TestFollowLinkErrors(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request, got: %v", r.URL)
+	})
+	if _, err := dc.FollowLink(Link{}); !errors.Is(err, ErrEmptyLink) {
+		t.Errorf("Expected %v, got: %v", ErrEmptyLink, err)
+	}
+	links := []Link{
+		{Href: "/discovery/v2/events/{id}", Templated: true},
+		{Href: "/discovery/v2/events{?keyword", Templated: true},
+	}
+	for _, link := range links {
+		if _, err := dc.FollowLink(link); !errors.Is(err, ErrTemplatedLink) {
+			t.Errorf("%s: Expected %v, got: %v", link.Href, ErrTemplatedLink, err)
+		}
+	}
+}
+
+func TestFollowLinkForeignHost(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("Expected no request, got: %v", r.URL)
+		},
+	))
+	defer other.Close()
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request, got: %v", r.URL)
+	})
+	otherUrl, _ := url.Parse(other.URL)
+	links := []Link{
+		{Href: other.URL + "/discovery/v2/events"},
+		{Href: "//" + otherUrl.Host + "/discovery/v2/events"},
+		{Href: "https://" + dc.ApiUrl.Host + "/discovery/v2/events"},
+	}
+	for _, link := range links {
+		if _, err := dc.FollowLink(link); !errors.Is(err, ErrForeignLink) {
+			t.Errorf("%s: Expected %v, got: %v", link.Href, ErrForeignLink, err)
+		}
+	}
+}
+
+func TestFirstLastPage(t *testing.T) {
+	var pages []string
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		number := r.URL.Query().Get("page")
+		pages = append(pages, number)
+		fmt.Fprintf(
+			w,
+			`{"_embedded": {"events": [{"id": "%s"}]}, "page": {"size": 20, "totalElements": 60, "totalPages": 3, "number": %s}}`,
+			number,
+			number,
+		)
+	})
+	var page PagedResponse
+	body := `{
+	  "_links": {
+	    "self": {"href": "/discovery/v2/events?page=1&size=20"},
+	    "first": {"href": "/discovery/v2/events?page=0&size=20"},
+	    "last": {"href": "/discovery/v2/events?page=2&size=20"}
+	  },
+	  "_embedded": {"events": [{"id": "1"}]},
+	  "page": {"size": 20, "totalElements": 60, "totalPages": 3, "number": 1}
+	}`
+	if err := json.Unmarshal([]byte(body), &page); err != nil {
+		t.Fatalf("Error decoding page json: %v", err)
+	}
+	first, err := dc.FirstPage(&page)
+	if err != nil || first.Page.Number != 0 {
+		t.Errorf("Expected page 0, got: %+v, %v", first, err)
+	}
+	last, err := dc.LastPage(&page)
+	if err != nil || last.Page.Number != 2 {
+		t.Errorf("Expected page 2, got: %+v, %v", last, err)
+	}
+	if fmt.Sprint(pages) != "[0 2]" {
+		t.Errorf("Expected %v, got: %v", "[0 2]", pages)
+	}
+
+	page.Page.TotalPages = 100
+	if _, err := page.LastPage(dc); !errors.Is(err, ErrMaxPageDepth) {
+		t.Errorf("Expected %v, got: %v", ErrMaxPageDepth, err)
+	}
+	page.Links = Links{}
+	if rs, err := page.FirstPage(dc); rs != nil || err != nil {
+		t.Errorf("Expected no first page, got: %v, %v", rs, err)
+	}
+}
+
+func TestUnexpectedContentType(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, "<!DOCTYPE html><html><body>Service Unavailable</body></html>")
+	})
+	_, err := dc.SearchEvents(QueryParams{})
+	if !errors.Is(err, ErrUnexpectedContentType) {
+		t.Fatalf("Expected %v, got: %v", ErrUnexpectedContentType, err)
+	}
+	if !strings.Contains(err.Error(), "text/html") ||
+		!strings.Contains(err.Error(), "<!DOCTYPE html>") {
+		t.Errorf("Expected content type and body in error, got: %v", err)
+	}
+}
+
+func TestUnexpectedContentTypeNotCached(t *testing.T) {
+	requests := 0
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, "<!DOCTYPE html><html><body>Service Unavailable</body></html>")
+	})
+	dc.Cache = NewMemoryCache()
+	for i := 0; i < 2; i++ {
+		_, err := dc.SearchEvents(QueryParams{})
+		if !errors.Is(err, ErrUnexpectedContentType) {
+			t.Errorf("%d: Expected %v, got: %v", i, ErrUnexpectedContentType, err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Expected %v, got: %v", 2, requests)
+	}
+}
+
+func TestMislabelledJSON(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, ` {"_embedded": {"events": [{"id": "1"}]}, "page": {"size": 20}}`)
+	})
+	rs, err := dc.SearchEvents(QueryParams{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rs.Embedded.Events) != 1 {
+		t.Errorf("Expected 1 event, got: %v", rs.Embedded.Events)
+	}
+}
+
+func TestContextWithAPIKey(t *testing.T) {
+	var keys []string
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.URL.Query().Get("apikey")+r.Header.Get(ApiKeyHeader))
+		fmt.Fprint(w, `{"page": {"size": 20}}`)
+	})
+	ctx := ContextWithAPIKey(context.Background(), "tenant-key")
+	if _, err := dc.SearchEventsContext(ctx, QueryParams{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := dc.SearchEvents(QueryParams{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	dc.AuthMode = AuthHeader
+	if _, err := dc.SearchEventsContext(ctx, QueryParams{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{"tenant-key", "12345", "tenant-key"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("Expected %v, got: %v", expected, keys)
+	}
+}
+
+func TestContextWithAPIKeyCache(t *testing.T) {
+	var requests []string
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		apiKey := r.URL.Query().Get("apikey")
+		requests = append(requests, apiKey+" "+r.Header.Get("If-None-Match"))
+		if apiKey != "tenant-a" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"fault": {"faultstring": "Invalid ApiKey"}}`)
+			return
+		}
+		w.Header().Set("ETag", `"a"`)
+		fmt.Fprint(w, `{"page": {"size": 20}}`)
+	})
+	dc.Cache = NewMemoryCache()
+	ctxA := ContextWithAPIKey(context.Background(), "tenant-a")
+	ctxB := ContextWithAPIKey(context.Background(), "tenant-b")
+	if _, err := dc.SearchEventsContext(ctxA, QueryParams{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := dc.SearchEventsContext(ctxA, QueryParams{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	_, err := dc.SearchEventsContext(ctxB, QueryParams{})
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected %v, got: %v", ErrUnauthorized, err)
+	}
+
+	// Without a cache, ETags are also kept per key
+	dc.Cache = nil
+	dc.UseETags = true
+	if _, err := dc.SearchEventsContext(ctxA, QueryParams{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	_, err = dc.SearchEventsContext(ctxB, QueryParams{})
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected %v, got: %v", ErrUnauthorized, err)
+	}
+	expected := []string{"tenant-a ", "tenant-b ", "tenant-a ", "tenant-b "}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("Expected %v, got: %v", expected, requests)
+	}
+}
+
+func TestDeduplicateEvents(t *testing.T) {
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"_embedded": {"events": [
+		  {"id": "1", "name": "Radiohead", "dates": {"start": {"dateTime": "2016-07-27T23:30:00Z"}}},
+		  {"id": "2", "name": "Radiohead", "dates": {"start": {"dateTime": "2016-07-27T23:30:00Z"}}},
+		  {"id": "3", "name": "Radiohead", "dates": {"start": {"dateTime": "2016-07-28T23:30:00Z"}}},
+		  {"id": "4", "name": "Radiohead"},
+		  {"id": "5", "name": "Radiohead"}
+		]}, "page": {"size": 20, "totalElements": 5, "totalPages": 1, "number": 0}}`)
+	})
+	ids := func(events []map[string]any) string {
+		var ids []any
+		for _, event := range events {
+			ids = append(ids, event["id"])
+		}
+		return fmt.Sprint(ids)
+	}
+
+	events, err := dc.AllEvents(QueryParams{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := ids(events); got != "[1 2 3 4 5]" {
+		t.Errorf("Expected no deduplication by default, got: %v", got)
+	}
+
+	dc.DeduplicateEvents = true
+	events, err = dc.AllEvents(QueryParams{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := ids(events); got != "[1 3 4 5]" {
+		t.Errorf("Expected %v, got: %v", "[1 3 4 5]", got)
+	}
+
+	dc.EventKey = func(event map[string]any) string {
+		return fmt.Sprint(event["name"])
+	}
+	eventsCh, errs := dc.StreamEvents(context.Background(), QueryParams{})
+	var streamed []map[string]any
+	for event := range eventsCh {
+		streamed = append(streamed, event)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := ids(streamed); got != "[1]" {
+		t.Errorf("Expected %v, got: %v", "[1]", got)
+	}
+}
+
+func TestEventsForAttraction(t *testing.T) {
+	var query url.Values
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		fmt.Fprint(w, `{"page": {"size": 20}}`)
+	})
+	before := time.Now().Add(-time.Second)
+	_, err := dc.EventsForAttraction(
+		"K8vZ91713wV",
+		QueryParams{AttractionID: []string{"ignored"}, Size: 5},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := query["attractionId"]; !reflect.DeepEqual(got, []string{"K8vZ91713wV"}) {
+		t.Errorf("Expected %v, got: %v", []string{"K8vZ91713wV"}, got)
+	}
+	if query.Get("sort") != SortDateAsc || query.Get("size") != "5" {
+		t.Errorf("Expected date order and size 5, got: %v", query)
+	}
+	start, err := time.Parse(DateTimeLayout, query.Get("startDateTime"))
+	if err != nil || start.Before(before.Truncate(time.Second)) {
+		t.Errorf("Expected start from now, got: %v", query.Get("startDateTime"))
+	}
+
+	_, err = dc.EventsForAttraction("K8vZ91713wV", QueryParams{
+		Sort:          SortRelevanceDesc,
+		StartDateTime: "2016-01-01T00:00:00Z",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if query.Get("sort") != SortRelevanceDesc ||
+		query.Get("startDateTime") != "2016-01-01T00:00:00Z" {
+		t.Errorf("Expected overridden defaults, got: %v", query)
+	}
+}
+
+func TestEventsForVenue(t *testing.T) {
+	var query url.Values
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		fmt.Fprint(w, `{"page": {"size": 20}}`)
+	})
+	_, err := dc.EventsForVenue(
+		"KovZpZA7AAEA",
+		QueryParams{VenueID: []string{"a", "b"}, Keyword: "radiohead"},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := query["venueId"]; !reflect.DeepEqual(got, []string{"KovZpZA7AAEA"}) {
+		t.Errorf("Expected %v, got: %v", []string{"KovZpZA7AAEA"}, got)
+	}
+	if query.Get("sort") != SortDateAsc || query.Get("keyword") != "radiohead" {
+		t.Errorf("Expected date order and keyword, got: %v", query)
+	}
+	if query.Get("startDateTime") == "" {
+		t.Errorf("Expected a startDateTime, got: %v", query)
+	}
+
+	_, err = dc.EventsForVenue("KovZpZA7AAEA", QueryParams{Sort: SortNameAsc})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if query.Get("sort") != SortNameAsc {
+		t.Errorf("Expected %v, got: %v", SortNameAsc, query.Get("sort"))
+	}
+}
+
+func TestBasePaths(t *testing.T) {
+	baseUrls := map[string]string{
+		"https://example.com/discovery/v2":     "https://example.com/discovery/v2/events",
+		"https://example.com/discovery/v2/":    "https://example.com/discovery/v2/events",
+		"https://example.com/tm/discovery/v2":  "https://example.com/tm/discovery/v2/events",
+		"https://example.com/tm/discovery/v2/": "https://example.com/tm/discovery/v2/events",
+		"https://example.com":                  "https://example.com/events",
+		"https://example.com/":                 "https://example.com/events",
+	}
+	for baseUrl, expected := range baseUrls {
+		apiUrl, _ := url.Parse(baseUrl)
+		dc := DiscoveryClient{ApiUrl: *apiUrl}
+		eventsUrl := dc.EventsUrl()
+		if eventsUrl.String() != expected {
+			t.Errorf("%s: Expected %v, got: %v", baseUrl, expected, eventsUrl.String())
+		}
+		client, err := NewDiscoveryClient("12345", WithBaseURL(baseUrl))
+		if err != nil {
+			t.Fatalf("%s: Unexpected error: %v", baseUrl, err)
+		}
+		eventsUrl = client.EventsUrl()
+		eventsUrl.RawQuery = ""
+		if eventsUrl.String() != expected {
+			t.Errorf("%s: Expected %v, got: %v", baseUrl, expected, eventsUrl.String())
+		}
+	}
+}
+
+func TestFollowLinkBehindProxy(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+			fmt.Fprint(w, `{"_links": {"next": {"href": "/discovery/v2/events?page=1&size=1"}}, "_embedded": {"events": [{"id": "1"}]}, "page": {"size": 1, "totalElements": 2, "totalPages": 2, "number": 0}}`)
+		},
+	))
+	t.Cleanup(server.Close)
+	baseUrls := map[string]string{
+		"/tm/discovery/v2/": "/tm/discovery/v2/events",
+		"/tm/discovery/v2":  "/tm/discovery/v2/events",
+		"/tm/":              "/tm/events",
+		"/tm":               "/tm/events",
+		"":                  "/events",
+	}
+	for basePath, expectedPath := range baseUrls {
+		paths = nil
+		dc, err := NewDiscoveryClient("12345", WithBaseURL(server.URL+basePath))
+		if err != nil {
+			t.Fatalf("%s: Unexpected error: %v", basePath, err)
+		}
+		rs, err := dc.SearchEvents(QueryParams{Size: 1})
+		if err != nil {
+			t.Fatalf("%s: Unexpected error: %v", basePath, err)
+		}
+		if _, err := rs.NextPage(dc); err != nil {
+			t.Fatalf("%s: Unexpected error: %v", basePath, err)
+		}
+		expected := []string{expectedPath, expectedPath}
+		if !reflect.DeepEqual(paths, expected) {
+			t.Errorf("%s: Expected %v, got: %v", basePath, expected, paths)
+		}
+	}
+}
+
+func TestResumeFrom(t *testing.T) {
+	dc := newPagedTestClient(t, 4)
+	it := dc.EventsIterator(QueryParams{Keyword: "radiohead", Size: 1})
+	if _, ok := it.Cursor(); ok {
+		t.Errorf("Expected no cursor before Next")
+	}
+	var cursor string
+	for it.Next() {
+		var ok bool
+		if cursor, ok = it.Cursor(); !ok {
+			t.Fatalf("Expected a cursor after page %d", it.Page().Page.Number)
+		}
+		if it.Page().Page.Number == 1 {
+			// Interrupted after the second page
+			break
+		}
+	}
+	params, err := ParseQueryParams(cursor)
+	if err != nil || params.Page != 2 || params.Keyword != "radiohead" {
+		t.Errorf("Expected page 2 of keyword radiohead, got: %+v, %v", params, err)
+	}
+
+	resumed, err := dc.ResumeFrom(cursor)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var ids []any
+	for resumed.Next() {
+		for _, event := range resumed.Page().Embedded.Events {
+			ids = append(ids, event["id"])
+		}
+	}
+	if err := resumed.Err(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fmt.Sprint(ids) != "[2 3]" {
+		t.Errorf("Expected %v, got: %v", "[2 3]", ids)
+	}
+	if _, ok := resumed.Page().Cursor(); ok {
+		t.Errorf("Expected no cursor after the last page")
+	}
+
+	// Without a size, the cursor has the page's
+	rs, err := dc.SearchEvents(QueryParams{Keyword: "radiohead"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cursor, _ = rs.Cursor()
+	if _, err := dc.ResumeFrom(cursor); err != nil {
+		t.Errorf("%q: Unexpected error: %v", cursor, err)
+	}
+
+	invalid := []string{
+		"page=%zz",
+		"",
+		"garbage",
+		"keyword=radiohead",
+		"page=2",
+		"page=two&size=1",
+		"page=2&size=1000",
+		"page=2&size=1&sort=sideways",
+	}
+	for _, cursor := range invalid {
+		if _, err := dc.ResumeFrom(cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%q: Expected %v, got: %v", cursor, ErrInvalidCursor, err)
+		}
+	}
+}
+
+func TestCursorOnlyForEventSearches(t *testing.T) {
+	dc := newPagedTestClient(t, 3)
+	searches := map[string]func(QueryParams) (*PagedResponse, error){
+		"venues":          dc.SearchVenues,
+		"attractions":     dc.SearchAttractions,
+		"classifications": dc.SearchClassifications,
+	}
+	for name, search := range searches {
+		rs, err := search(QueryParams{Size: 1})
+		if err != nil {
+			t.Fatalf("%s: Unexpected error: %v", name, err)
+		}
+		if cursor, ok := rs.Cursor(); ok {
+			t.Errorf("%s: Expected no cursor, got: %v", name, cursor)
+		}
+	}
+	rs, err := dc.SearchEvents(QueryParams{Size: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	next, err := rs.NextPage(dc)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := next.Cursor(); !ok {
+		t.Errorf("Expected a cursor for the next page of an event search")
+	}
+}
+
+func TestHooks(t *testing.T) {
+	attempts := 0
+	dc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"page": {"size": 20}}`)
+	})
+	var requests []RequestInfo
+	var responses []ResponseInfo
+	dc.MaxRetries = 1
+	dc.Hooks = &Hooks{
+		OnRequest: func(info RequestInfo) {
+			requests = append(requests, info)
+		},
+		OnResponse: func(info ResponseInfo) {
+			responses = append(responses, info)
+		},
+	}
+	if _, err := dc.SearchEvents(QueryParams{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(requests) != 2 || len(responses) != 2 {
+		t.Fatalf("Expected 2 requests and responses, got: %v, %v", requests, responses)
+	}
+	for i, status := range []int{http.StatusTooManyRequests, http.StatusOK} {
+		if requests[i].Attempt != i || responses[i].Attempt != i {
+			t.Errorf("Expected attempt %d, got: %+v, %+v", i, requests[i], responses[i])
+		}
+		if responses[i].StatusCode != status || responses[i].Err != nil {
+			t.Errorf("Expected status %d, got: %+v", status, responses[i])
+		}
+		if strings.Contains(responses[i].URL, "12345") ||
+			!strings.Contains(responses[i].URL, "/events") {
+			t.Errorf("Expected redacted events URL, got: %v", responses[i].URL)
+		}
+	}
+
+	// Hooks may be left unset
+	dc.Hooks = &Hooks{}
+	if _, err := dc.SearchEvents(QueryParams{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
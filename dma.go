@@ -0,0 +1,114 @@
+package discoverygo
+
+import "strings"
+
+// Designated market area IDs, for QueryParams.DmaID
+// See: https://developer.ticketmaster.com/products-and-docs/apis/discovery-api/v2/#supported-dma
+const (
+	DmaAllOfUS      = 200
+	DmaAtlanta      = 220
+	DmaAustin       = 222
+	DmaBaltimore    = 224
+	DmaBoston       = 235
+	DmaCharlotte    = 245
+	DmaChicago      = 249
+	DmaCincinnati   = 251
+	DmaCleveland    = 253
+	DmaColumbus     = 259
+	DmaDallas       = 261
+	DmaDenver       = 264
+	DmaDetroit      = 266
+	DmaHartford     = 296
+	DmaHouston      = 300
+	DmaIndianapolis = 303
+	DmaKansasCity   = 311
+	DmaLasVegas     = 319
+	DmaLosAngeles   = 324
+	DmaMiami        = 334
+	DmaMilwaukee    = 335
+	DmaMinneapolis  = 336
+	DmaNashville    = 343
+	DmaNewOrleans   = 344
+	DmaNewYork      = 345
+	DmaOrlando      = 351
+	DmaPhiladelphia = 358
+	DmaPhoenix      = 359
+	DmaPittsburgh   = 360
+	DmaPortland     = 361
+	DmaRaleigh      = 366
+	DmaSacramento   = 374
+	DmaSaltLakeCity = 376
+	DmaSanAntonio   = 378
+	DmaSanDiego     = 379
+	DmaSanFrancisco = 380
+	DmaSeattle      = 383
+	DmaStLouis      = 393
+	DmaTampa        = 396
+	DmaWashington   = 409
+)
+
+// dmas are the designated market areas with constants above: the largest
+// US markets. The Discovery API has no endpoint listing DMAs, so this is
+// taken from the API documentation
+var dmas = []Dma{
+	{ID: DmaAllOfUS, Name: "All of US"},
+	{ID: DmaAtlanta, Name: "Atlanta"},
+	{ID: DmaAustin, Name: "Austin"},
+	{ID: DmaBaltimore, Name: "Baltimore"},
+	{ID: DmaBoston, Name: "Boston"},
+	{ID: DmaCharlotte, Name: "Charlotte"},
+	{ID: DmaChicago, Name: "Chicago"},
+	{ID: DmaCincinnati, Name: "Cincinnati"},
+	{ID: DmaCleveland, Name: "Cleveland"},
+	{ID: DmaColumbus, Name: "Columbus, OH"},
+	{ID: DmaDallas, Name: "Dallas - Fort Worth"},
+	{ID: DmaDenver, Name: "Denver"},
+	{ID: DmaDetroit, Name: "Detroit"},
+	{ID: DmaHartford, Name: "Hartford & New Haven"},
+	{ID: DmaHouston, Name: "Houston"},
+	{ID: DmaIndianapolis, Name: "Indianapolis"},
+	{ID: DmaKansasCity, Name: "Kansas City"},
+	{ID: DmaLasVegas, Name: "Las Vegas"},
+	{ID: DmaLosAngeles, Name: "Los Angeles"},
+	{ID: DmaMiami, Name: "Miami - Fort Lauderdale"},
+	{ID: DmaMilwaukee, Name: "Milwaukee"},
+	{ID: DmaMinneapolis, Name: "Minneapolis - St. Paul"},
+	{ID: DmaNashville, Name: "Nashville"},
+	{ID: DmaNewOrleans, Name: "New Orleans"},
+	{ID: DmaNewYork, Name: "New York"},
+	{ID: DmaOrlando, Name: "Orlando - Daytona Beach - Melbourne"},
+	{ID: DmaPhiladelphia, Name: "Philadelphia"},
+	{ID: DmaPhoenix, Name: "Phoenix"},
+	{ID: DmaPittsburgh, Name: "Pittsburgh"},
+	{ID: DmaPortland, Name: "Portland, OR"},
+	{ID: DmaRaleigh, Name: "Raleigh - Durham"},
+	{ID: DmaSacramento, Name: "Sacramento - Stockton - Modesto"},
+	{ID: DmaSaltLakeCity, Name: "Salt Lake City"},
+	{ID: DmaSanAntonio, Name: "San Antonio"},
+	{ID: DmaSanDiego, Name: "San Diego"},
+	{ID: DmaSanFrancisco, Name: "San Francisco - Oakland - San Jose"},
+	{ID: DmaSeattle, Name: "Seattle - Tacoma"},
+	{ID: DmaStLouis, Name: "St. Louis"},
+	{ID: DmaTampa, Name: "Tampa - St. Petersburg"},
+	{ID: DmaWashington, Name: "Washington, DC"},
+}
+
+// DmaList returns the known designated market areas
+func DmaList() []Dma {
+	return append([]Dma(nil), dmas...)
+}
+
+// LookupDma returns the known designated market area whose name contains
+// the given name, ignoring case, e.g. "new york", "Hartford" or "oakland"
+func LookupDma(name string) (Dma, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return Dma{}, false
+	}
+	for _, dma := range dmas {
+		if strings.Contains(strings.ToLower(dma.Name), name) {
+			return dma, true
+		}
+	}
+	return Dma{}, false
+}
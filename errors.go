@@ -0,0 +1,144 @@
+package discoverygo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// APIError is returned when the Discovery API responds with a status
+// code other than 200 OK
+type APIError struct {
+	StatusCode int `json:"-"`
+	// Raw response body
+	Body string `json:"-"`
+	// Fault is set for gateway errors, such as an invalid API key
+	Fault *Fault `json:"fault,omitempty"`
+	// Errors is set for API errors, such as a resource not being found
+	Errors []ErrorDetail `json:"errors,omitempty"`
+	// Code is the error code from either response shape: the Fault's error
+	// code, e.g. "oauth.v2.InvalidApiKey", or that of the first of Errors,
+	// e.g. "DIS1004". It's empty if the body matched neither
+	Code string `json:"-"`
+	// Message is the Fault's faultstring, or the detail of the first of
+	// Errors
+	Message string `json:"-"`
+}
+
+// Fault is the error returned by the API gateway, e.g.
+// {"fault": {"faultstring": "Invalid ApiKey", "detail": {...}}}
+type Fault struct {
+	FaultString string      `json:"faultstring"`
+	Detail      FaultDetail `json:"detail"`
+}
+
+// FaultDetail holds the error code of a Fault
+type FaultDetail struct {
+	ErrorCode string `json:"errorcode"`
+}
+
+// ErrorDetail is an error returned by the Discovery API, e.g.
+// {"errors": [{"code": "DIS1004", "detail": "Resource not found", ...}]}
+type ErrorDetail struct {
+	Code   string `json:"code"`
+	Detail string `json:"detail"`
+	Status string `json:"status"`
+}
+
+func (e *APIError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf(
+			"Status code: %d: %s: %s",
+			e.StatusCode,
+			e.Code,
+			e.Message,
+		)
+	}
+	return fmt.Sprintf("Status code: %d: %s", e.StatusCode, e.Body)
+}
+
+// ErrUnauthorized matches an *APIError for a 401 Unauthorized response,
+// e.g. for an invalid API key, with errors.Is
+var ErrUnauthorized = errors.New("Unauthorized")
+
+// invalidApiKeyCode is the fault error code for an invalid API key
+const invalidApiKeyCode = "oauth.v2.InvalidApiKey"
+
+// Is reports whether the error matches target, so an *APIError for a 401
+// Unauthorized response matches ErrUnauthorized, and one for a fault with
+// an invalid API key matches ErrInvalidApiKey
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrInvalidApiKey:
+		return e.Code == invalidApiKeyCode
+	}
+	return false
+}
+
+// newAPIError reads the body of the given response into an APIError,
+// parsing any fault or errors it contains
+func newAPIError(resp *http.Response) *APIError {
+	body, _ := io.ReadAll(resp.Body)
+	apiErr := &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	// The body isn't guaranteed to be JSON, in which case only the status
+	// code and raw body are set
+	_ = json.Unmarshal(body, apiErr)
+	switch {
+	case apiErr.Fault != nil:
+		apiErr.Code = apiErr.Fault.Detail.ErrorCode
+		apiErr.Message = apiErr.Fault.FaultString
+	case len(apiErr.Errors) > 0:
+		apiErr.Code = apiErr.Errors[0].Code
+		apiErr.Message = apiErr.Errors[0].Detail
+	}
+	return apiErr
+}
+
+// RequestError wraps the error from a failed request with the redacted URL
+// requested and how long the request took. Requests only fail with a
+// RequestError if the client's ErrorTiming is set. The wrapped error, e.g.
+// an *APIError, can still be found with errors.As
+type RequestError struct {
+	Method   string
+	URL      string
+	Duration time.Duration
+	Err      error
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf(
+		"%s %s failed after %v: %v",
+		e.Method,
+		e.URL,
+		e.Duration.Round(time.Millisecond),
+		e.Err,
+	)
+}
+
+// Unwrap returns the error the request failed with
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// requestError wraps err in a RequestError for the given request, started
+// at the given time, if the client's ErrorTiming is set
+func (d *DiscoveryClient) requestError(
+	req *http.Request,
+	start time.Time,
+	err error,
+) error {
+	if !d.ErrorTiming || err == nil {
+		return err
+	}
+	return &RequestError{
+		Method:   req.Method,
+		URL:      RedactURL(*req.URL),
+		Duration: time.Since(start),
+		Err:      err,
+	}
+}
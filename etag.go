@@ -0,0 +1,102 @@
+package discoverygo
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// ErrNotModified is returned when the client's UseETags is set and the API
+// responds 304 Not Modified, but the client has no stored body for the
+// request to return in its place
+var ErrNotModified = errors.New("Not modified")
+
+// maxETags is the number of responses the client stores ETags (and bodies)
+// for. Once it's reached, an arbitrary entry is evicted for each new one
+const maxETags = 1000
+
+// etagEntry is the ETag of a response, with the body and content type
+// returned in place of a later 304 Not Modified response
+type etagEntry struct {
+	etag        string
+	body        []byte
+	contentType string
+}
+
+// setIfNoneMatch sets the If-None-Match header on the given request to the
+// ETag from the last response to an identical request, if there was one
+func (d *DiscoveryClient) setIfNoneMatch(req *http.Request) {
+	if !d.UseETags || uncached(req) || req.Header.Get("If-None-Match") != "" {
+		return
+	}
+	d.mu.Lock()
+	entry, ok := d.etags[d.cacheKey(req)]
+	d.mu.Unlock()
+	if ok {
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+}
+
+// recordETag stores the ETag and body of a successful response, for the
+// next identical request. The body is read in full and replaced, so the
+// response can still be decoded
+func (d *DiscoveryClient) recordETag(
+	req *http.Request,
+	resp *http.Response,
+) (*http.Response, error) {
+	if !d.UseETags || uncached(req) || resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		return resp, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	key := d.cacheKey(req)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.etags == nil {
+		d.etags = map[string]etagEntry{}
+	}
+	if _, ok := d.etags[key]; !ok && len(d.etags) >= maxETags {
+		for evicted := range d.etags {
+			delete(d.etags, evicted)
+			break
+		}
+	}
+	d.etags[key] = etagEntry{
+		etag:        etag,
+		body:        body,
+		contentType: resp.Header.Get("Content-Type"),
+	}
+	return resp, nil
+}
+
+// replayETag replaces the body of a 304 Not Modified response with the
+// stored body of the last successful response to an identical request. It
+// returns false if there's none
+func (d *DiscoveryClient) replayETag(
+	req *http.Request,
+	resp *http.Response,
+) bool {
+	d.mu.Lock()
+	entry, ok := d.etags[d.cacheKey(req)]
+	d.mu.Unlock()
+	if !ok {
+		return false
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(entry.body))
+	resp.ContentLength = int64(len(entry.body))
+	if entry.contentType != "" {
+		resp.Header.Set("Content-Type", entry.contentType)
+	}
+	return true
+}
@@ -0,0 +1,221 @@
+package discoverygo
+
+import (
+	"strconv"
+	"time"
+)
+
+// Helpers for extracting typed values from the untyped resources in
+// EmbeddedResponse. They tolerate missing or mistyped fields, returning
+// zero values rather than failing
+
+// stringField returns m[key] if it's a string
+func stringField(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+// floatField returns m[key] if it's a number
+func floatField(m map[string]any, key string) float64 {
+	f, _ := m[key].(float64)
+	return f
+}
+
+// numberField returns m[key] if it's a number, or a string containing one
+func numberField(m map[string]any, key string) (float64, bool) {
+	switch v := m[key].(type) {
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// timeField returns m[key] parsed as a date-time, or the zero time if it's
+// missing or invalid
+func timeField(m map[string]any, key string) time.Time {
+	t, _ := ParseDateTime(stringField(m, key))
+	return t
+}
+
+// boolField returns m[key] if it's a boolean
+func boolField(m map[string]any, key string) bool {
+	b, _ := m[key].(bool)
+	return b
+}
+
+// mapSlice returns m[key] as a slice of objects, skipping any elements that
+// aren't objects
+func mapSlice(m map[string]any, key string) []map[string]any {
+	items, _ := m[key].([]any)
+	var maps []map[string]any
+	for _, item := range items {
+		if itemMap, ok := item.(map[string]any); ok {
+			maps = append(maps, itemMap)
+		}
+	}
+	return maps
+}
+
+// EventPriceRanges returns the price ranges of an event from
+// EmbeddedResponse.Events. Missing fields are left as zero values, and it
+// returns nil if the event has no price ranges
+func EventPriceRanges(event map[string]any) []PriceRange {
+	var priceRanges []PriceRange
+	for _, item := range mapSlice(event, "priceRanges") {
+		priceRanges = append(priceRanges, PriceRange{
+			Type:     stringField(item, "type"),
+			Currency: stringField(item, "currency"),
+			Min:      floatField(item, "min"),
+			Max:      floatField(item, "max"),
+		})
+	}
+	return priceRanges
+}
+
+// VenueLocation returns the coordinates of a venue from
+// EmbeddedResponse.Venues (or an event's embedded venues), parsed from the
+// strings the API encodes them as. It returns false if the venue has no
+// location, or either coordinate is missing or invalid
+func VenueLocation(venue map[string]any) (Location, bool) {
+	location, ok := venue["location"].(map[string]any)
+	if !ok {
+		return Location{}, false
+	}
+	lat, ok := numberField(location, "latitude")
+	if !ok {
+		return Location{}, false
+	}
+	long, ok := numberField(location, "longitude")
+	if !ok {
+		return Location{}, false
+	}
+	return Location{Latitude: lat, Longitude: long}, true
+}
+
+// Images returns the images of an event, venue or attraction from
+// EmbeddedResponse. It returns nil if there are none
+func Images(item map[string]any) []Image {
+	var images []Image
+	for _, image := range mapSlice(item, "images") {
+		images = append(images, Image{
+			Ratio:    stringField(image, "ratio"),
+			URL:      stringField(image, "url"),
+			Width:    int(floatField(image, "width")),
+			Height:   int(floatField(image, "height")),
+			Fallback: boolField(image, "fallback"),
+		})
+	}
+	return images
+}
+
+// BestImage returns the URL of the smallest image of an event, venue or
+// attraction that's at least minWidth wide, or of the largest image if
+// none are that wide. It returns false if there are no images
+func BestImage(item map[string]any, minWidth int) (string, bool) {
+	image, ok := BestImageOf(Images(item), minWidth)
+	return image.URL, ok
+}
+
+// BestImageOf returns the smallest of the given images that's at least
+// minWidth wide, or the largest if none are that wide. Images without a
+// URL are skipped. It returns false if there are no images
+func BestImageOf(images []Image, minWidth int) (Image, bool) {
+	var best Image
+	found := false
+	for _, image := range images {
+		if image.URL == "" {
+			continue
+		}
+		switch {
+		case !found:
+			best = image
+		case best.Width < minWidth:
+			// Nothing wide enough yet, so prefer anything larger
+			if image.Width > best.Width {
+				best = image
+			}
+		case image.Width >= minWidth && image.Width < best.Width:
+			best = image
+		}
+		found = true
+	}
+	return best, found
+}
+
+// EventSales returns the public on-sale window and presales of an event
+// from EmbeddedResponse.Events. Missing or invalid dates are left as zero
+// times. Check PublicSale.StartKnown before relying on the public start,
+// since the API sets startTBD or startTBA when it isn't decided
+func EventSales(event map[string]any) Sales {
+	sales, _ := event["sales"].(map[string]any)
+	public, _ := sales["public"].(map[string]any)
+	rs := Sales{
+		Public: PublicSale{
+			StartDateTime: timeField(public, "startDateTime"),
+			StartTBD:      boolField(public, "startTBD"),
+			StartTBA:      boolField(public, "startTBA"),
+			EndDateTime:   timeField(public, "endDateTime"),
+		},
+	}
+	for _, presale := range mapSlice(sales, "presales") {
+		rs.Presales = append(rs.Presales, Presale{
+			Name:          stringField(presale, "name"),
+			Description:   stringField(presale, "description"),
+			URL:           stringField(presale, "url"),
+			StartDateTime: timeField(presale, "startDateTime"),
+			EndDateTime:   timeField(presale, "endDateTime"),
+		})
+	}
+	return rs
+}
+
+// EventDistance returns the distance of an event from the search location
+// and its unit as given by the API, e.g. 3.2 and "MILES". Events only have
+// a distance when searched by location. It returns false if the event has
+// no distance
+func EventDistance(event map[string]any) (float64, string, bool) {
+	distance, ok := numberField(event, "distance")
+	if !ok {
+		return 0, "", false
+	}
+	return distance, stringField(event, "units"), true
+}
+
+// EventVenues returns the venues embedded in an event from
+// EmbeddedResponse.Events, so they can be used without requesting each
+// venue. It returns nil if the event has no embedded venues
+func EventVenues(event map[string]any) []map[string]any {
+	embedded, _ := event["_embedded"].(map[string]any)
+	return mapSlice(embedded, "venues")
+}
+
+// EventDates returns the dates of an event from EmbeddedResponse.Events.
+// Missing fields are left as zero values
+func EventDates(event map[string]any) Dates {
+	dates, _ := event["dates"].(map[string]any)
+	start, _ := dates["start"].(map[string]any)
+	status, _ := dates["status"].(map[string]any)
+	return Dates{
+		Start: StartDate{
+			LocalDate:      stringField(start, "localDate"),
+			LocalTime:      stringField(start, "localTime"),
+			DateTime:       stringField(start, "dateTime"),
+			DateTBD:        boolField(start, "dateTBD"),
+			DateTBA:        boolField(start, "dateTBA"),
+			TimeTBA:        boolField(start, "timeTBA"),
+			NoSpecificTime: boolField(start, "noSpecificTime"),
+		},
+		Timezone:         stringField(dates, "timezone"),
+		Status:           DateStatus{Code: stringField(status, "code")},
+		SpanMultipleDays: boolField(dates, "spanMultipleDays"),
+	}
+}
+
+// StartTime returns when an event from EmbeddedResponse.Events starts. See
+// Dates.StartTime
+func StartTime(event map[string]any) (time.Time, bool) {
+	return EventDates(event).StartTime()
+}
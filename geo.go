@@ -0,0 +1,110 @@
+package discoverygo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// geohashAlphabet is the base32 alphabet used by geohashes
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// Geohash encodes the given coordinates as a geohash of the given length,
+// for use as QueryParams.GeoPoint. A precision of 9 is accurate to within
+// a few meters; precision is clamped to between 1 and 12
+func Geohash(lat, long float64, precision int) string {
+	if precision < 1 {
+		precision = 1
+	} else if precision > 12 {
+		precision = 12
+	}
+	latRange := [2]float64{-90, 90}
+	longRange := [2]float64{-180, 180}
+	hash := make([]byte, 0, precision)
+	even := true
+	bit, ch := 0, 0
+	for len(hash) < precision {
+		// Bits alternate between longitude and latitude, starting with
+		// longitude, each halving the remaining range
+		r, v := &latRange, lat
+		if even {
+			r, v = &longRange, long
+		}
+		mid := (r[0] + r[1]) / 2
+		ch <<= 1
+		if v >= mid {
+			ch |= 1
+			r[0] = mid
+		} else {
+			r[1] = mid
+		}
+		even = !even
+		if bit++; bit == 5 {
+			hash = append(hash, geohashAlphabet[ch])
+			bit, ch = 0, 0
+		}
+	}
+	return string(hash)
+}
+
+// FormatLatLong formats the given coordinates for QueryParams.LatLong, as
+// "lat,long" with no spaces or trailing zeros
+func FormatLatLong(lat, long float64) string {
+	return strconv.FormatFloat(lat, 'f', -1, 64) + "," +
+		strconv.FormatFloat(long, 'f', -1, 64)
+}
+
+// SetRadius sets Radius and Unit together, to the given distance in the
+// given unit (UnitMiles or UnitKm). An empty unit sets DefaultUnit. The
+// radius needs a location to search around, LatLong, PostalCode or
+// GeoPoint, which Validate checks for
+func (q *QueryParams) SetRadius(radius int, unit string) {
+	if unit == "" {
+		unit = DefaultUnit
+	}
+	q.Radius = strconv.Itoa(radius)
+	q.Unit = unit
+}
+
+// validateRadius checks that a Radius has a location to search around
+func (q QueryParams) validateRadius() error {
+	if q.Radius == "" || q.LatLong != "" || q.PostalCode != "" ||
+		q.GeoPoint != "" {
+		return nil
+	}
+	return fmt.Errorf(
+		"Invalid radius %q: requires latlong, postalCode or geoPoint",
+		q.Radius,
+	)
+}
+
+// NearbyEvents returns events within the given number of miles of the
+// given coordinates, matching the given query parameters. It overrides the
+// LatLong, Radius and Unit parameters
+func (d *DiscoveryClient) NearbyEvents(
+	lat, long float64,
+	radiusMiles int,
+	queryParams QueryParams,
+) (*PagedResponse, error) {
+	return d.NearbyEventsContext(
+		context.Background(),
+		lat,
+		long,
+		radiusMiles,
+		queryParams,
+	)
+}
+
+// NearbyEventsContext returns events within the given number of miles of
+// the given coordinates. The request is bound to the given context
+func (d *DiscoveryClient) NearbyEventsContext(
+	ctx context.Context,
+	lat, long float64,
+	radiusMiles int,
+	queryParams QueryParams,
+) (*PagedResponse, error) {
+	queryParams.LatLong = FormatLatLong(lat, long)
+	queryParams.Radius = strconv.Itoa(radiusMiles)
+	queryParams.Unit = UnitMiles
+	return d.SearchEventsContext(ctx, queryParams)
+}
@@ -0,0 +1,47 @@
+package discoverygo
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// gzipBody is a gzip-decoded response body. Closing it closes both the
+// gzip reader and the underlying body
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (g *gzipBody) Close() error {
+	gzErr := g.Reader.Close()
+	if err := g.body.Close(); err != nil {
+		return err
+	}
+	return gzErr
+}
+
+// decompress replaces the body of a gzip-encoded response with one that
+// decodes it. Since requests set Accept-Encoding themselves, the transport
+// doesn't do this automatically
+func decompress(resp *http.Response) (*http.Response, error) {
+	if resp.Header.Get("Content-Encoding") != "gzip" {
+		return resp, nil
+	}
+	gz, err := gzip.NewReader(resp.Body)
+	if errors.Is(err, io.EOF) {
+		// Empty body
+		return resp, nil
+	}
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	resp.Body = &gzipBody{Reader: gz, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return resp, nil
+}
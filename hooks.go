@@ -0,0 +1,77 @@
+package discoverygo
+
+import (
+	"net/http"
+	"time"
+)
+
+// Hooks are called around each HTTP request the client sends, including
+// retries, e.g. to record metrics. Responses served from the client's
+// Cache don't send a request, so don't call them. Either may be nil
+type Hooks struct {
+	// Called before each request is sent
+	OnRequest func(info RequestInfo)
+	// Called after each request, once its response headers are received or
+	// it fails
+	OnResponse func(info ResponseInfo)
+}
+
+// RequestInfo describes a request about to be sent, for Hooks.OnRequest
+type RequestInfo struct {
+	Method string
+	// URL of the request, with the API key redacted
+	URL string
+	// Number of times the request has already been tried
+	Attempt int
+}
+
+// ResponseInfo describes the result of a request, for Hooks.OnResponse
+type ResponseInfo struct {
+	Method string
+	// URL of the request, with the API key redacted
+	URL string
+	// Number of times the request had already been tried
+	Attempt int
+	// Status code of the response, or zero if the request failed
+	StatusCode int
+	// Time from sending the request to receiving the response headers
+	Duration time.Duration
+	// Error the request failed with, if any
+	Err error
+}
+
+// onRequest calls the client's OnRequest hook, if there is one
+func (d *DiscoveryClient) onRequest(req *http.Request, attempt int) {
+	if d.Hooks == nil || d.Hooks.OnRequest == nil {
+		return
+	}
+	d.Hooks.OnRequest(RequestInfo{
+		Method:  req.Method,
+		URL:     RedactURL(*req.URL),
+		Attempt: attempt,
+	})
+}
+
+// onResponse calls the client's OnResponse hook, if there is one
+func (d *DiscoveryClient) onResponse(
+	req *http.Request,
+	attempt int,
+	resp *http.Response,
+	err error,
+	duration time.Duration,
+) {
+	if d.Hooks == nil || d.Hooks.OnResponse == nil {
+		return
+	}
+	info := ResponseInfo{
+		Method:   req.Method,
+		URL:      RedactURL(*req.URL),
+		Attempt:  attempt,
+		Duration: duration,
+		Err:      err,
+	}
+	if resp != nil {
+		info.StatusCode = resp.StatusCode
+	}
+	d.Hooks.OnResponse(info)
+}
@@ -0,0 +1,287 @@
+package discoverygo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// PageIterator walks the pages of a search, following the next link of
+// each page until there are no more:
+//
+//	it := client.EventsIterator(queryParams)
+//	for it.Next() {
+//		events = append(events, it.Page().Embedded.Events...)
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type PageIterator struct {
+	ctx    context.Context
+	client *DiscoveryClient
+	first  func(ctx context.Context) (*PagedResponse, error)
+	page   *PagedResponse
+	err    error
+	done   bool
+}
+
+// EventsIterator returns a PageIterator over the results of an event search
+// for the given query parameters. No request is made until Next is called
+func (d *DiscoveryClient) EventsIterator(queryParams QueryParams) *PageIterator {
+	return d.EventsIteratorContext(context.Background(), queryParams)
+}
+
+// EventsIteratorContext returns a PageIterator over the results of an
+// event search for the given query parameters. Each page is requested with
+// the given context, and iteration stops as soon as it's done
+func (d *DiscoveryClient) EventsIteratorContext(
+	ctx context.Context,
+	queryParams QueryParams,
+) *PageIterator {
+	return &PageIterator{
+		ctx:    ctx,
+		client: d,
+		first: func(ctx context.Context) (*PagedResponse, error) {
+			return d.SearchEventsContext(ctx, queryParams)
+		},
+	}
+}
+
+// Next fetches the next page of results, returning false when there are no
+// more pages, the search matched no results, or an error occurred. Check
+// Err after Next returns false
+func (it *PageIterator) Next() bool {
+	if it.done {
+		return false
+	}
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		it.done = true
+		return false
+	}
+	var page *PagedResponse
+	var err error
+	if it.page == nil {
+		page, err = it.first(it.ctx)
+	} else {
+		page, err = it.page.NextPageContext(it.ctx, it.client)
+	}
+	if err != nil || page == nil {
+		it.err = err
+		it.done = true
+		return false
+	}
+	it.page = page
+	if page.Empty() {
+		// Nothing matched, so there's no page worth returning
+		it.done = true
+		return false
+	}
+	return true
+}
+
+// Page returns the current page of results
+func (it *PageIterator) Page() *PagedResponse {
+	return it.page
+}
+
+// Err returns the error that stopped iteration, if any
+func (it *PageIterator) Err() error {
+	return it.err
+}
+
+// EachEvent calls fn for each event matching the given query parameters,
+// across all pages. Iteration stops at the first error returned by fn or
+// encountered fetching a page
+func (d *DiscoveryClient) EachEvent(
+	queryParams QueryParams,
+	fn func(event map[string]any) error,
+) error {
+	return d.EachEventContext(context.Background(), queryParams, fn)
+}
+
+// EachEventContext calls fn for each event matching the given query
+// parameters, across all pages. Pages are requested with the given context
+func (d *DiscoveryClient) EachEventContext(
+	ctx context.Context,
+	queryParams QueryParams,
+	fn func(event map[string]any) error,
+) error {
+	it := d.EventsIteratorContext(ctx, queryParams)
+	for it.Next() {
+		for _, event := range it.Page().Embedded.Events {
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+	}
+	return it.Err()
+}
+
+// AllEvents returns every event matching the given query parameters,
+// following the next link of each page. If the API's page depth limit is
+// reached before the last page, the events fetched so far are returned
+// along with an error wrapping ErrMaxPageDepth, so the results are known
+// to be incomplete
+func (d *DiscoveryClient) AllEvents(
+	queryParams QueryParams,
+) ([]map[string]any, error) {
+	return d.AllEventsContext(context.Background(), queryParams)
+}
+
+// AllEventsContext returns every event matching the given query
+// parameters. Pages are requested with the given context. If the client's
+// Concurrency is greater than one, pages after the first are requested in
+// parallel, by page number. If DeduplicateEvents is set, only the first of
+// events with the same EventKey is returned
+func (d *DiscoveryClient) AllEventsContext(
+	ctx context.Context,
+	queryParams QueryParams,
+) ([]map[string]any, error) {
+	if d.Concurrency > 1 {
+		return d.allEventsParallel(ctx, queryParams)
+	}
+	var events []map[string]any
+	keep := d.eventFilter()
+	it := d.EventsIteratorContext(ctx, queryParams)
+	for it.Next() {
+		events = append(events, filterEvents(it.Page().Embedded.Events, keep)...)
+	}
+	if err := it.Err(); err != nil {
+		if errors.Is(err, ErrMaxPageDepth) {
+			return events, fmt.Errorf(
+				"results truncated after %d events: %w",
+				len(events),
+				err,
+			)
+		}
+		return nil, err
+	}
+	return events, nil
+}
+
+// allEventsParallel fetches the first page of results, then the remaining
+// pages (up to the API's depth limit) with up to d.Concurrency requests in
+// flight at once. Pages are assembled in order. The first error cancels
+// any outstanding requests
+func (d *DiscoveryClient) allEventsParallel(
+	ctx context.Context,
+	queryParams QueryParams,
+) ([]map[string]any, error) {
+	first, err := d.SearchEventsContext(ctx, queryParams)
+	if err != nil {
+		return nil, err
+	}
+	start := first.Page.Number
+	last := first.maxReachablePage(d.maxResultDepth())
+	truncated := last < first.Page.TotalPages-1
+	if last < start {
+		last = start
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pages := make([][]map[string]any, last-start+1)
+	pages[0] = first.Embedded.Events
+	numbers := make(chan int)
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for i := 0; i < d.Concurrency && i < last-start; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for number := range numbers {
+				params := queryParams
+				params.Page = number
+				params.Size = first.Page.Size
+				page, err := d.SearchEventsContext(ctx, params)
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				pages[number-start] = page.Embedded.Events
+			}
+		}()
+	}
+send:
+	for number := start + 1; number <= last; number++ {
+		select {
+		case numbers <- number:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(numbers)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var events []map[string]any
+	for _, page := range pages {
+		events = append(events, page...)
+	}
+	events = filterEvents(events, d.eventFilter())
+	if truncated {
+		return events, fmt.Errorf(
+			"results truncated after %d events: %w",
+			len(events),
+			ErrMaxPageDepth,
+		)
+	}
+	return events, nil
+}
+
+// StreamEvents sends each event matching the given query parameters on the
+// returned channel, fetching pages in the background as they're consumed,
+// so only one page is held in memory at a time. The events channel is
+// closed when there are no more events, or when an error occurs or the
+// context is done. The error, if any, is then sent on the error channel,
+// which is closed afterwards. As with AllEvents, reaching the API's page
+// depth limit is reported as an error wrapping ErrMaxPageDepth, and
+// duplicate events are dropped if DeduplicateEvents is set
+func (d *DiscoveryClient) StreamEvents(
+	ctx context.Context,
+	queryParams QueryParams,
+) (<-chan map[string]any, <-chan error) {
+	events := make(chan map[string]any)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(events)
+		sent := 0
+		keep := d.eventFilter()
+		it := d.EventsIteratorContext(ctx, queryParams)
+		for it.Next() {
+			for _, event := range filterEvents(it.Page().Embedded.Events, keep) {
+				select {
+				case events <- event:
+					sent++
+				case <-ctx.Done():
+					errs <- ctx.Err()
+					return
+				}
+			}
+		}
+		if err := it.Err(); err != nil {
+			if errors.Is(err, ErrMaxPageDepth) {
+				err = fmt.Errorf(
+					"results truncated after %d events: %w",
+					sent,
+					err,
+				)
+			}
+			errs <- err
+		}
+	}()
+	return events, errs
+}
@@ -0,0 +1,44 @@
+package discoverygo
+
+import (
+	"net/url"
+	"strings"
+)
+
+// keywordReserved holds the characters the Discovery API's search syntax
+// treats specially in the keyword parameter
+const keywordReserved = `+-&|!(){}[]^"~*?:\/`
+
+// EscapeKeyword escapes characters in the given keyword that the Discovery
+// API's search syntax treats specially, so they're matched literally. Each
+// of the characters +-&|!(){}[]^"~*?:\/ is prefixed with a backslash, so
+// for example "Simon & Garfunkel" becomes "Simon \& Garfunkel"
+func EscapeKeyword(keyword string) string {
+	if !strings.ContainsAny(keyword, keywordReserved) {
+		return keyword
+	}
+	var b strings.Builder
+	b.Grow(len(keyword) * 2)
+	for _, r := range keyword {
+		if strings.ContainsRune(keywordReserved, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// searchURL returns the given URL with the query parameters applied, as
+// QueryParams.UpdateURL does, with the keyword escaped first if the
+// client's EscapeKeywords is set. Keywords are only escaped here, where a
+// search is built, since links the API returns already carry the escaped
+// keyword
+func (d *DiscoveryClient) searchURL(
+	u url.URL,
+	queryParams QueryParams,
+) (*url.URL, error) {
+	if d.EscapeKeywords && queryParams.Keyword != "" {
+		queryParams.Keyword = EscapeKeyword(queryParams.Keyword)
+	}
+	return queryParams.UpdateURL(u, d.apiKey())
+}
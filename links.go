@@ -0,0 +1,186 @@
+package discoverygo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrEmptyLink is returned by FollowLink for a link with no href
+var ErrEmptyLink = errors.New("Link has no href")
+
+// ErrTemplatedLink is returned by FollowLink for a templated link with
+// variables that must be expanded before it can be followed, e.g. {id}, and
+// by ExpandLink for templates it can't expand
+var ErrTemplatedLink = errors.New("Link needs template expansion")
+
+// ErrForeignLink is returned by FollowLink for a link to a different scheme
+// or host than the client's ApiUrl, which would be sent the API key
+var ErrForeignLink = errors.New("Link is to another host")
+
+// ExpandLink returns the href of the given link with its template
+// expanded with the given variables, as in RFC 6570. Simple expressions,
+// e.g. {id}, must have a variable, or an error wrapping ErrTemplatedLink is
+// returned. Query expressions, e.g. {?locale} or {&page,size}, add only the
+// variables given. Other operators and modifiers aren't supported, and
+// return an error wrapping ErrTemplatedLink
+func ExpandLink(link Link, vars map[string]string) (string, error) {
+	var b strings.Builder
+	rest := link.Href
+	for {
+		before, after, found := strings.Cut(rest, "{")
+		b.WriteString(before)
+		if !found {
+			return b.String(), nil
+		}
+		expr, remaining, closed := strings.Cut(after, "}")
+		if !closed {
+			return "", fmt.Errorf(
+				"%w: unclosed expression in %s",
+				ErrTemplatedLink,
+				link.Href,
+			)
+		}
+		if err := expandExpression(&b, expr, vars); err != nil {
+			return "", fmt.Errorf("%w: %v in %s", ErrTemplatedLink, err, link.Href)
+		}
+		rest = remaining
+	}
+}
+
+// expandExpression writes the expansion of a single template expression,
+// without its braces, to b
+func expandExpression(
+	b *strings.Builder,
+	expr string,
+	vars map[string]string,
+) error {
+	operator := ""
+	if expr != "" && strings.ContainsRune("+#./;?&=,!@|", rune(expr[0])) {
+		operator, expr = expr[:1], expr[1:]
+	}
+	if operator != "" && operator != "?" && operator != "&" {
+		return fmt.Errorf("unsupported operator %q", operator)
+	}
+	names := strings.Split(expr, ",")
+	for _, name := range names {
+		if name == "" || strings.ContainsAny(name, ":*") {
+			return fmt.Errorf("unsupported variable %q", name)
+		}
+	}
+	if operator == "" {
+		for i, name := range names {
+			value, ok := vars[name]
+			if !ok {
+				return fmt.Errorf("missing variable %q", name)
+			}
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(templateEscape(value))
+		}
+		return nil
+	}
+	sep := operator
+	for _, name := range names {
+		value, ok := vars[name]
+		if !ok {
+			continue
+		}
+		b.WriteString(sep)
+		b.WriteString(templateEscape(name))
+		b.WriteByte('=')
+		b.WriteString(templateEscape(value))
+		sep = "&"
+	}
+	return nil
+}
+
+// templateEscape percent-encodes everything in s but unreserved
+// characters, as RFC 6570 simple and query expansion do
+func templateEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			c == '-', c == '.', c == '_', c == '~':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// resolveLink returns the URL of the given link, resolved against the
+// client's API URL (with its path in place of the API's base path), with
+// any optional query expressions removed. Links to another scheme or host
+// return an error wrapping ErrForeignLink
+func (d *DiscoveryClient) resolveLink(link Link) (*url.URL, error) {
+	if link.Href == "" {
+		return nil, ErrEmptyLink
+	}
+	// Expanding with no variables drops optional query expressions
+	href, err := ExpandLink(link, nil)
+	if err != nil {
+		return nil, err
+	}
+	baseUrl := d.ApiUrl
+	u, err := baseUrl.Parse(href)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != baseUrl.Scheme || u.Host != baseUrl.Host {
+		return nil, fmt.Errorf("%w: %s://%s", ErrForeignLink, u.Scheme, u.Host)
+	}
+	// Hrefs are absolute paths from the API's host, e.g. /discovery/v2/...,
+	// so swap that base path for ApiUrl's, e.g. of a proxy
+	if strings.HasPrefix(u.Path, discoveryBasePath+"/") {
+		u.Path = d.basePath() + strings.TrimPrefix(u.Path, discoveryBasePath)
+		u.RawPath = ""
+	}
+	return u, nil
+}
+
+// discoveryBasePath is the path of DiscoveryApiUrl, which the API's links
+// start with
+const discoveryBasePath = "/discovery/v2"
+
+// basePath returns ApiUrl's path without a trailing slash, which takes the
+// place of discoveryBasePath in links, e.g. "/tm" for a proxy at
+// https://example.com/tm/, "/tm/discovery/v2" for one at
+// https://example.com/tm/discovery/v2, or "" for a server at the root
+func (d *DiscoveryClient) basePath() string {
+	return strings.TrimRight(d.ApiUrl.Path, "/")
+}
+
+// FollowLink returns the paged response from the given link, e.g. one
+// from a response's _links. The href is resolved against ApiUrl, and the
+// API key is sent as for any other request, so links to another scheme or
+// host return an error wrapping ErrForeignLink. Optional query expressions of
+// templated links are dropped, but links with other template variables
+// return an error wrapping ErrTemplatedLink. Expand those with ExpandLink
+// first
+func (d *DiscoveryClient) FollowLink(link Link) (*PagedResponse, error) {
+	return d.FollowLinkContext(context.Background(), link)
+}
+
+// FollowLinkContext returns the paged response from the given link. The
+// request is bound to the given context
+func (d *DiscoveryClient) FollowLinkContext(
+	ctx context.Context,
+	link Link,
+) (*PagedResponse, error) {
+	u, err := d.resolveLink(link)
+	if err != nil {
+		return nil, err
+	}
+	var rs PagedResponse
+	if err := d.doRequest(ctx, *u, &rs); err != nil {
+		return nil, err
+	}
+	return &rs, nil
+}
@@ -0,0 +1,42 @@
+package discoverygo
+
+import "net/http"
+
+// requestIDHeaders are the response headers checked, in order, for an ID
+// identifying the request to Ticketmaster support
+var requestIDHeaders = []string{
+	"X-Request-Id",
+	"X-Correlation-Id",
+	"Request-Id",
+}
+
+// ResponseMeta is the status code and headers of a successful response,
+// e.g. for reading rate limits or correlating a request with Ticketmaster
+// support
+type ResponseMeta struct {
+	StatusCode int
+	Headers    http.Header
+	// ID of the request, if the response has an X-Request-Id (or similar)
+	// header
+	RequestID string
+	// Whether the API responded 304 Not Modified, and the body is the one
+	// stored with its ETag (see UseETags)
+	NotModified bool
+}
+
+// metaSetter is implemented by responses that can hold a ResponseMeta
+type metaSetter interface {
+	setMeta(meta *ResponseMeta)
+}
+
+// newResponseMeta returns the ResponseMeta for the given response
+func newResponseMeta(resp *http.Response) *ResponseMeta {
+	meta := &ResponseMeta{StatusCode: resp.StatusCode, Headers: resp.Header}
+	for _, header := range requestIDHeaders {
+		if id := resp.Header.Get(header); id != "" {
+			meta.RequestID = id
+			break
+		}
+	}
+	return meta
+}
@@ -1,13 +1,267 @@
 package discoverygo
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"log"
-	"net/http"
+	"strconv"
+	"time"
 )
 
+// Event is an event from the Discovery API
+// See: https://developer.ticketmaster.com/products-and-docs/apis/discovery-api/v2/#event-details-v2
+type Event struct {
+	ID              string           `json:"id"`
+	Name            string           `json:"name"`
+	Type            string           `json:"type,omitempty"`
+	URL             string           `json:"url,omitempty"`
+	Locale          string           `json:"locale,omitempty"`
+	Test            bool             `json:"test"`
+	Info            string           `json:"info,omitempty"`
+	PleaseNote      string           `json:"pleaseNote,omitempty"`
+	Dates           Dates            `json:"dates"`
+	PriceRanges     []PriceRange     `json:"priceRanges,omitempty"`
+	Classifications []Classification `json:"classifications,omitempty"`
+	Promoter        *Promoter        `json:"promoter,omitempty"`
+	Images          []Image          `json:"images,omitempty"`
+	Sales           *Sales           `json:"sales,omitempty"`
+}
+
+// Dates holds the start date and status of an event
+type Dates struct {
+	Start            StartDate  `json:"start"`
+	Timezone         string     `json:"timezone,omitempty"`
+	Status           DateStatus `json:"status"`
+	SpanMultipleDays bool       `json:"spanMultipleDays,omitempty"`
+}
+
+// StartDate is when an event starts. DateTime is in UTC, while LocalDate
+// and LocalTime are in the venue's timezone
+type StartDate struct {
+	LocalDate      string `json:"localDate,omitempty"`
+	LocalTime      string `json:"localTime,omitempty"`
+	DateTime       string `json:"dateTime,omitempty"`
+	DateTBD        bool   `json:"dateTBD"`
+	DateTBA        bool   `json:"dateTBA"`
+	TimeTBA        bool   `json:"timeTBA"`
+	NoSpecificTime bool   `json:"noSpecificTime"`
+}
+
+// DateStatus is the sale status of an event, e.g. "onsale"
+type DateStatus struct {
+	Code string `json:"code"`
+}
+
+// PriceRange is a range of ticket prices for an event
+type PriceRange struct {
+	Type     string  `json:"type,omitempty"`
+	Currency string  `json:"currency,omitempty"`
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+}
+
+// Classification is the segment, genre and sub-genre of an event
+type Classification struct {
+	Primary  bool     `json:"primary"`
+	Segment  Segment  `json:"segment"`
+	Genre    Genre    `json:"genre"`
+	SubGenre SubGenre `json:"subGenre"`
+}
+
+// Segment is the top level of the classification hierarchy, e.g. "Music"
+type Segment struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Genres in the segment. Only the classifications endpoints return these
+	Genres []Genre `json:"-"`
+}
+
+// UnmarshalJSON decodes a segment, moving the genres embedded in it by the
+// classifications endpoints to Genres
+func (s *Segment) UnmarshalJSON(data []byte) error {
+	type segment Segment
+	var v struct {
+		segment
+		Embedded struct {
+			Genres []Genre `json:"genres"`
+		} `json:"_embedded"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*s = Segment(v.segment)
+	s.Genres = v.Embedded.Genres
+	return nil
+}
+
+// Genre is the second level of the classification hierarchy, e.g. "Rock"
+type Genre struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Sub-genres in the genre. Only the classifications endpoints return
+	// these
+	SubGenres []SubGenre `json:"-"`
+}
+
+// UnmarshalJSON decodes a genre, moving the sub-genres embedded in it by
+// the classifications endpoints to SubGenres
+func (g *Genre) UnmarshalJSON(data []byte) error {
+	type genre Genre
+	var v struct {
+		genre
+		Embedded struct {
+			SubGenres []SubGenre `json:"subgenres"`
+		} `json:"_embedded"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*g = Genre(v.genre)
+	g.SubGenres = v.Embedded.SubGenres
+	return nil
+}
+
+// SubGenre is the third level of the classification hierarchy,
+// e.g. "Alternative Rock"
+type SubGenre struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Sales is when tickets for an event go on sale
+type Sales struct {
+	Public   PublicSale `json:"public"`
+	Presales []Presale  `json:"presales,omitempty"`
+}
+
+// PublicSale is the general public on-sale window for an event. When
+// StartTBD or StartTBA is set, the start hasn't been decided or announced,
+// and StartDateTime shouldn't be relied on
+type PublicSale struct {
+	StartDateTime time.Time `json:"startDateTime"`
+	StartTBD      bool      `json:"startTBD"`
+	StartTBA      bool      `json:"startTBA"`
+	EndDateTime   time.Time `json:"endDateTime"`
+}
+
+// StartKnown reports whether the start of the sale has been announced
+func (s PublicSale) StartKnown() bool {
+	return !s.StartTBD && !s.StartTBA && !s.StartDateTime.IsZero()
+}
+
+// OnSaleAt reports whether tickets are on sale to the public at the given
+// time. It's false if the start of the sale isn't known
+func (s PublicSale) OnSaleAt(t time.Time) bool {
+	if !s.StartKnown() || t.Before(s.StartDateTime) {
+		return false
+	}
+	return s.EndDateTime.IsZero() || t.Before(s.EndDateTime)
+}
+
+// Presale is a presale window for an event, e.g. for fan club members
+type Presale struct {
+	Name          string    `json:"name,omitempty"`
+	Description   string    `json:"description,omitempty"`
+	URL           string    `json:"url,omitempty"`
+	StartDateTime time.Time `json:"startDateTime"`
+	EndDateTime   time.Time `json:"endDateTime"`
+}
+
+// Image is one of the sizes of an image of an event, venue or attraction
+type Image struct {
+	Ratio    string `json:"ratio,omitempty"`
+	URL      string `json:"url"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	Fallback bool   `json:"fallback"`
+}
+
+// Promoter is the promoter of an event
+type Promoter struct {
+	ID          string `json:"id"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Venue is a venue from the Discovery API
+// See: https://developer.ticketmaster.com/products-and-docs/apis/discovery-api/v2/#venue-details-v2
+type Venue struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Type       string    `json:"type,omitempty"`
+	URL        string    `json:"url,omitempty"`
+	Locale     string    `json:"locale,omitempty"`
+	Test       bool      `json:"test"`
+	PostalCode string    `json:"postalCode,omitempty"`
+	Timezone   string    `json:"timezone,omitempty"`
+	City       City      `json:"city"`
+	State      State     `json:"state"`
+	Country    Country   `json:"country"`
+	Address    Address   `json:"address"`
+	Location   *Location `json:"location,omitempty"`
+	Markets    []Market  `json:"markets,omitempty"`
+	Dmas       []Dma     `json:"dmas,omitempty"`
+	Images     []Image   `json:"images,omitempty"`
+}
+
+// City is the city a venue is in
+type City struct {
+	Name string `json:"name"`
+}
+
+// State is the state a venue is in
+type State struct {
+	Name      string `json:"name"`
+	StateCode string `json:"stateCode,omitempty"`
+}
+
+// Country is the country a venue is in
+type Country struct {
+	Name        string `json:"name"`
+	CountryCode string `json:"countryCode,omitempty"`
+}
+
+// Address is the street address of a venue
+type Address struct {
+	Line1 string `json:"line1,omitempty"`
+	Line2 string `json:"line2,omitempty"`
+	Line3 string `json:"line3,omitempty"`
+}
+
+// Location is a latitude/longitude pair. The API encodes both as strings
+type Location struct {
+	Latitude  float64 `json:"latitude,string"`
+	Longitude float64 `json:"longitude,string"`
+}
+
+// Market is a market a venue belongs to
+type Market struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// Dma is a designated market area a venue belongs to
+type Dma struct {
+	ID   int    `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// Attraction is an artist, team or performer from the Discovery API
+// See: https://developer.ticketmaster.com/products-and-docs/apis/discovery-api/v2/#attraction-details-v2
+type Attraction struct {
+	ID              string           `json:"id"`
+	Name            string           `json:"name"`
+	Type            string           `json:"type,omitempty"`
+	URL             string           `json:"url,omitempty"`
+	Locale          string           `json:"locale,omitempty"`
+	Test            bool             `json:"test"`
+	Classifications []Classification `json:"classifications,omitempty"`
+	Images          []Image          `json:"images,omitempty"`
+	// Number of upcoming events, by source. "_total" is the sum
+	UpcomingEvents map[string]int `json:"upcomingEvents,omitempty"`
+}
+
 // Link is a link to another resource (see API spec)
 type Link struct {
 	Href      string `json:"href,omitempty"`
@@ -16,9 +270,11 @@ type Link struct {
 
 // Links is a collection of links to other resources, for pagination
 type Links struct {
-	Self Link `json:"self,omitempty"`
-	Next Link `json:"next,omitempty"`
-	Prev Link `json:"prev,omitempty"`
+	Self  Link `json:"self,omitempty"`
+	Next  Link `json:"next,omitempty"`
+	Prev  Link `json:"prev,omitempty"`
+	First Link `json:"first,omitempty"`
+	Last  Link `json:"last,omitempty"`
 }
 
 // Page indicates the current page of a paginated response
@@ -36,62 +292,271 @@ type EmbeddedResponse struct {
 	Venues          []map[string]any `json:"venues,omitempty"`
 	Attractions     []map[string]any `json:"attractions,omitempty"`
 	Classifications []map[string]any `json:"classifications,omitempty"`
+	// Any other embedded resources, by key, e.g. "products" from the
+	// suggest endpoint
+	Extra map[string][]map[string]any `json:"-"`
+}
+
+// embeddedResponse has the fields of EmbeddedResponse without its methods
+type embeddedResponse EmbeddedResponse
+
+// UnmarshalJSON decodes the embedded resources, putting any without a
+// field of their own in Extra. Values that aren't arrays of objects are
+// ignored
+func (e *EmbeddedResponse) UnmarshalJSON(data []byte) error {
+	var known embeddedResponse
+	if err := json.Unmarshal(data, &known); err != nil {
+		return err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	*e = EmbeddedResponse(known)
+	for key, value := range all {
+		switch key {
+		case "events", "venues", "attractions", "classifications":
+			continue
+		}
+		var items []map[string]any
+		if err := json.Unmarshal(value, &items); err != nil {
+			continue
+		}
+		if e.Extra == nil {
+			e.Extra = map[string][]map[string]any{}
+		}
+		e.Extra[key] = items
+	}
+	return nil
+}
+
+// MarshalJSON encodes the embedded resources, including those in Extra
+func (e EmbeddedResponse) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(embeddedResponse(e))
+	if err != nil || len(e.Extra) == 0 {
+		return data, err
+	}
+	var all map[string]any
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	for key, items := range e.Extra {
+		if _, ok := all[key]; !ok {
+			all[key] = items
+		}
+	}
+	return json.Marshal(all)
+}
+
+// Spellcheck holds spelling suggestions for the keyword of a search,
+// returned when the includeSpellcheck parameter is "yes"
+type Spellcheck struct {
+	Query       string                 `json:"query"`
+	Suggestions []SpellcheckSuggestion `json:"suggestions"`
+}
+
+// SpellcheckSuggestion is a suggested correction for a search keyword
+type SpellcheckSuggestion struct {
+	Suggestion string  `json:"suggestion"`
+	Score      float64 `json:"score,omitempty"`
+}
+
+// Strings returns the suggested corrections, in the order the API
+// returned them
+func (s *Spellcheck) Strings() []string {
+	if s == nil {
+		return nil
+	}
+	suggestions := make([]string, 0, len(s.Suggestions))
+	for _, suggestion := range s.Suggestions {
+		suggestions = append(suggestions, suggestion.Suggestion)
+	}
+	return suggestions
 }
 
 // PagedResponse is a response from the Discovery API - it can be paginated
 // with the `NextPage` and `PrevPage` methods
 type PagedResponse struct {
+	Links      Links            `json:"_links,omitempty"`
+	Page       Page             `json:"page"`
+	Embedded   EmbeddedResponse `json:"_embedded"`
+	Spellcheck *Spellcheck      `json:"spellcheck,omitempty"`
+	// Raw response body, if the client's KeepRawResponse is set
+	Raw []byte `json:"-"`
+	// Status code and headers of the response
+	Meta *ResponseMeta `json:"-"`
+
+	// Query parameters of the search the response came from
+	params *QueryParams
+	// Whether the search was of events, which cursors can resume
+	eventSearch bool
+}
+
+// SuggestResponse is a response from the suggest endpoint
+type SuggestResponse struct {
 	Links    Links            `json:"_links,omitempty"`
-	Page     Page             `json:"page"`
 	Embedded EmbeddedResponse `json:"_embedded"`
+	// Status code and headers of the response
+	Meta *ResponseMeta `json:"-"`
 }
 
-// NextPage returns the next page of results from the Discovery API, for
-// the given paged response
-func (p *PagedResponse) NextPage(
-	client *DiscoveryClient,
-) (*PagedResponse, error) {
-	if p.Page.Size*p.Page.Number >= 1000 {
-		return nil, fmt.Errorf(
-			"Max page depth reached (%d)",
-			p.Page.Size*p.Page.Number,
-		)
-	}
-	baseUrl := client.ApiUrl
-	if p.Links.Next.Href == "" {
-		return nil, nil
-	}
+func (s *SuggestResponse) setMeta(meta *ResponseMeta) {
+	s.Meta = meta
+}
 
-	rel, _ := baseUrl.Parse(p.Links.Next.Href)
-	q := rel.Query()
-	q.Set("apikey", client.ApiKey)
-	rel.RawQuery = q.Encode()
+func (p *PagedResponse) setRaw(body []byte) {
+	p.Raw = body
+}
 
-	resp, err := http.Get(rel.String())
-	defer resp.Body.Close()
+func (p *PagedResponse) setMeta(meta *ResponseMeta) {
+	p.Meta = meta
+}
 
+// ClassificationTree decodes the classifications in a response from
+// SearchClassifications, so the segment -> genre -> sub-genre hierarchy can
+// be walked:
+//
+//	for _, c := range classifications {
+//		for _, genre := range c.Segment.Genres {
+//			for _, subGenre := range genre.SubGenres {
+//				...
+//			}
+//		}
+//	}
+func (p *PagedResponse) ClassificationTree() ([]Classification, error) {
+	data, err := json.Marshal(p.Embedded.Classifications)
 	if err != nil {
-		log.Println(err)
 		return nil, err
 	}
+	var classifications []Classification
+	if err := json.Unmarshal(data, &classifications); err != nil {
+		return nil, err
+	}
+	return classifications, nil
+}
 
-	log.Printf("Status code: %v", resp.StatusCode)
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf(
-			"Status code: %d: %s",
-			resp.StatusCode,
-			body,
-		)
+// Params returns the query parameters of the search this page of results
+// came from, with Page set to this page's number. It returns false if the
+// response didn't come from a search, e.g. if it was decoded directly
+func (p *PagedResponse) Params() (QueryParams, bool) {
+	if p.params == nil {
+		return QueryParams{}, false
 	}
+	return p.params.Clone(), true
+}
 
-	var rs PagedResponse
-	decodeErr := json.NewDecoder(resp.Body).Decode(&rs)
-	if decodeErr != nil {
-		log.Println(decodeErr)
-		return nil, decodeErr
+// inheritParams sets the query parameters of p to those of the page it was
+// navigated to from, and whether it was an event search
+func (p *PagedResponse) inheritParams(from *PagedResponse) {
+	if from.params == nil {
+		return
 	}
-	return &rs, nil
+	params := from.params.WithPage(p.Page.Number)
+	p.params = &params
+	p.eventSearch = from.eventSearch
+}
+
+// ErrMaxPageDepth is returned when paginating past the deepest page the
+// Discovery API allows (it only returns the first 1000 results of a search)
+var ErrMaxPageDepth = errors.New("Max page depth reached")
+
+// DefaultMaxResultDepth is the number of results the Discovery API will
+// page through: a page can only be requested if page*size is less than it
+const DefaultMaxResultDepth = 1000
+
+// checkDepth returns an error wrapping ErrMaxPageDepth if the given page
+// number, at this response's page size, is past the client's
+// MaxResultDepth
+func (p *PagedResponse) checkDepth(client *DiscoveryClient, number int) error {
+	if offset := p.Page.Size * number; offset >= client.maxResultDepth() {
+		return fmt.Errorf("%w (%d)", ErrMaxPageDepth, offset)
+	}
+	return nil
+}
+
+// Empty reports whether the search matched no results: there's nothing
+// embedded, and the first page reports no elements or pages. The API may
+// omit the page and links of an empty response, or leave them partly
+// populated
+func (p *PagedResponse) Empty() bool {
+	e := p.Embedded
+	return p.Page.TotalElements == 0 &&
+		p.Page.TotalPages == 0 &&
+		p.Page.Number == 0 &&
+		len(e.Events) == 0 &&
+		len(e.Venues) == 0 &&
+		len(e.Attractions) == 0 &&
+		len(e.Classifications) == 0 &&
+		len(e.Extra) == 0
+}
+
+// emptyPage reports whether the response has page details saying the
+// search matched no results, in which case its links aren't followed
+func (p *PagedResponse) emptyPage() bool {
+	return p.Page.Size > 0 && p.Empty()
+}
+
+// HasNext reports whether there's a page of results after this one
+func (p *PagedResponse) HasNext() bool {
+	return p.Links.Next.Href != "" && !p.emptyPage()
+}
+
+// HasPrev reports whether there's a page of results before this one
+func (p *PagedResponse) HasPrev() bool {
+	return p.Links.Prev.Href != "" && !p.emptyPage()
+}
+
+// MaxReachablePage returns the number of the last page of results that can
+// be requested at this page size, given the API only pages through the
+// first MaxResultDepth results of the given client (DefaultMaxResultDepth
+// if it's nil or unset). Pages are numbered from zero, so for a Size of 200
+// it's 4 by default (unless there are fewer pages than that)
+func (p *PagedResponse) MaxReachablePage(client *DiscoveryClient) int {
+	if client == nil {
+		return p.maxReachablePage(DefaultMaxResultDepth)
+	}
+	return p.maxReachablePage(client.maxResultDepth())
+}
+
+// maxReachablePage returns the number of the last page of results that
+// can be requested at this page size, for the given result depth
+func (p *PagedResponse) maxReachablePage(depth int) int {
+	last := p.Page.TotalPages - 1
+	if p.Page.Size > 0 && (depth-1)/p.Page.Size < last {
+		last = (depth - 1) / p.Page.Size
+	}
+	if last < 0 {
+		return 0
+	}
+	return last
+}
+
+// NextPage returns the next page of results from the Discovery API, for
+// the given paged response
+func (p *PagedResponse) NextPage(
+	client *DiscoveryClient,
+) (*PagedResponse, error) {
+	return p.NextPageContext(context.Background(), client)
+}
+
+// NextPageContext returns the next page of results from the Discovery API,
+// for the given paged response. The request is bound to the given context
+func (p *PagedResponse) NextPageContext(
+	ctx context.Context,
+	client *DiscoveryClient,
+) (*PagedResponse, error) {
+	if !p.HasNext() {
+		return nil, nil
+	}
+	if err := p.checkDepth(client, p.Page.Number+1); err != nil {
+		return nil, err
+	}
+	rs, err := client.FollowLinkContext(ctx, p.Links.Next)
+	if err != nil {
+		return nil, err
+	}
+	rs.inheritParams(p)
+	return rs, nil
 }
 
 // PreviousPage returns the previous page of results from the Discovery API, for
@@ -99,45 +564,226 @@ func (p *PagedResponse) NextPage(
 func (p *PagedResponse) PreviousPage(
 	client *DiscoveryClient,
 ) (*PagedResponse, error) {
-	if p.Page.Size*p.Page.Number >= 1000 {
-		return nil, fmt.Errorf(
-			"Max page depth reached (%d)",
-			p.Page.Size*p.Page.Number,
-		)
-	}
-	baseUrl := client.ApiUrl
-	if p.Links.Prev.Href == "" {
+	return p.PreviousPageContext(context.Background(), client)
+}
+
+// PreviousPageContext returns the previous page of results from the Discovery API,
+// for the given paged response. The request is bound to the given context
+func (p *PagedResponse) PreviousPageContext(
+	ctx context.Context,
+	client *DiscoveryClient,
+) (*PagedResponse, error) {
+	if !p.HasPrev() {
 		return nil, nil
 	}
+	if err := p.checkDepth(client, p.Page.Number-1); err != nil {
+		return nil, err
+	}
+	rs, err := client.FollowLinkContext(ctx, p.Links.Prev)
+	if err != nil {
+		return nil, err
+	}
+	rs.inheritParams(p)
+	return rs, nil
+}
 
-	rel, _ := baseUrl.Parse(p.Links.Prev.Href)
-	q := rel.Query()
-	q.Set("apikey", client.ApiKey)
-	rel.RawQuery = q.Encode()
+// FirstPage returns the first page of results from the Discovery API, for
+// the given paged response. It returns nil if the response has no first
+// link
+func (p *PagedResponse) FirstPage(
+	client *DiscoveryClient,
+) (*PagedResponse, error) {
+	return p.FirstPageContext(context.Background(), client)
+}
+
+// FirstPageContext returns the first page of results from the Discovery
+// API, for the given paged response. The request is bound to the given
+// context
+func (p *PagedResponse) FirstPageContext(
+	ctx context.Context,
+	client *DiscoveryClient,
+) (*PagedResponse, error) {
+	if p.Links.First.Href == "" || p.emptyPage() {
+		return nil, nil
+	}
+	rs, err := client.FollowLinkContext(ctx, p.Links.First)
+	if err != nil {
+		return nil, err
+	}
+	rs.inheritParams(p)
+	return rs, nil
+}
 
-	resp, err := http.Get(rel.String())
-	defer resp.Body.Close()
+// LastPage returns the last page of results from the Discovery API, for
+// the given paged response. It returns nil if the response has no last
+// link, and an error wrapping ErrMaxPageDepth if the last page is beyond
+// the API's depth limit (see MaxReachablePage)
+func (p *PagedResponse) LastPage(
+	client *DiscoveryClient,
+) (*PagedResponse, error) {
+	return p.LastPageContext(context.Background(), client)
+}
 
+// LastPageContext returns the last page of results from the Discovery
+// API, for the given paged response. The request is bound to the given
+// context
+func (p *PagedResponse) LastPageContext(
+	ctx context.Context,
+	client *DiscoveryClient,
+) (*PagedResponse, error) {
+	if p.Links.Last.Href == "" || p.emptyPage() {
+		return nil, nil
+	}
+	if err := p.checkDepth(client, p.Page.TotalPages-1); err != nil {
+		return nil, err
+	}
+	rs, err := client.FollowLinkContext(ctx, p.Links.Last)
 	if err != nil {
-		log.Println(err)
 		return nil, err
 	}
+	rs.inheritParams(p)
+	return rs, nil
+}
+
+// ErrPageOutOfRange is returned by GoToPage for a page number outside the
+// results
+var ErrPageOutOfRange = errors.New("Page out of range")
+
+// ErrNoSelfLink is returned by GoToPage when a paged response has no self
+// link to take its query from
+var ErrNoSelfLink = errors.New("Response has no self link")
+
+// GoToPage returns the given page of results, by reissuing the query from
+// this response's self link with the page number replaced. Unlike NextPage
+// it doesn't depend on the next/prev links. Pages are numbered from zero
+func (p *PagedResponse) GoToPage(
+	client *DiscoveryClient,
+	number int,
+) (*PagedResponse, error) {
+	return p.GoToPageContext(context.Background(), client, number)
+}
 
-	log.Printf("Status code: %v", resp.StatusCode)
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
+// GoToPageContext returns the given page of results. The request is bound
+// to the given context
+func (p *PagedResponse) GoToPageContext(
+	ctx context.Context,
+	client *DiscoveryClient,
+	number int,
+) (*PagedResponse, error) {
+	totalPages := p.Page.TotalPages
+	if number < 0 ||
+		(totalPages > 0 && number >= totalPages) ||
+		(p.Empty() && number > 0) {
 		return nil, fmt.Errorf(
-			"Status code: %d: %s",
-			resp.StatusCode,
-			body,
+			"%w: %d (%d pages)",
+			ErrPageOutOfRange,
+			number,
+			p.Page.TotalPages,
 		)
 	}
+	if err := p.checkDepth(client, number); err != nil {
+		return nil, err
+	}
+	// Templated self links end with e.g. {&page,size,sort}
+	rel, err := client.resolveLink(p.Links.Self)
+	if errors.Is(err, ErrEmptyLink) {
+		return nil, ErrNoSelfLink
+	}
+	if err != nil {
+		return nil, err
+	}
+	q := rel.Query()
+	q.Set("page", strconv.Itoa(number))
+	if p.Page.Size > 0 {
+		q.Set("size", strconv.Itoa(p.Page.Size))
+	}
+	rel.RawQuery = q.Encode()
 
 	var rs PagedResponse
-	decodeErr := json.NewDecoder(resp.Body).Decode(&rs)
-	if decodeErr != nil {
-		log.Println(decodeErr)
-		return nil, decodeErr
+	if err := client.doRequest(ctx, *rel, &rs); err != nil {
+		return nil, err
 	}
+	rs.inheritParams(p)
 	return &rs, nil
 }
+
+// NextPage returns the page of results following the given paged response.
+// It's equivalent to p.NextPage(d)
+func (d *DiscoveryClient) NextPage(p *PagedResponse) (*PagedResponse, error) {
+	return p.NextPageContext(context.Background(), d)
+}
+
+// NextPageContext returns the page of results following the given paged
+// response. The request is bound to the given context
+func (d *DiscoveryClient) NextPageContext(
+	ctx context.Context,
+	p *PagedResponse,
+) (*PagedResponse, error) {
+	return p.NextPageContext(ctx, d)
+}
+
+// PreviousPage returns the page of results preceding the given paged
+// response. It's equivalent to p.PreviousPage(d)
+func (d *DiscoveryClient) PreviousPage(
+	p *PagedResponse,
+) (*PagedResponse, error) {
+	return p.PreviousPageContext(context.Background(), d)
+}
+
+// PreviousPageContext returns the page of results preceding the given paged
+// response. The request is bound to the given context
+func (d *DiscoveryClient) PreviousPageContext(
+	ctx context.Context,
+	p *PagedResponse,
+) (*PagedResponse, error) {
+	return p.PreviousPageContext(ctx, d)
+}
+
+// FirstPage returns the first page of the results the given paged response
+// belongs to. It's equivalent to p.FirstPage(d)
+func (d *DiscoveryClient) FirstPage(p *PagedResponse) (*PagedResponse, error) {
+	return p.FirstPageContext(context.Background(), d)
+}
+
+// FirstPageContext returns the first page of the results the given paged
+// response belongs to. The request is bound to the given context
+func (d *DiscoveryClient) FirstPageContext(
+	ctx context.Context,
+	p *PagedResponse,
+) (*PagedResponse, error) {
+	return p.FirstPageContext(ctx, d)
+}
+
+// LastPage returns the last page of the results the given paged response
+// belongs to. It's equivalent to p.LastPage(d)
+func (d *DiscoveryClient) LastPage(p *PagedResponse) (*PagedResponse, error) {
+	return p.LastPageContext(context.Background(), d)
+}
+
+// LastPageContext returns the last page of the results the given paged
+// response belongs to. The request is bound to the given context
+func (d *DiscoveryClient) LastPageContext(
+	ctx context.Context,
+	p *PagedResponse,
+) (*PagedResponse, error) {
+	return p.LastPageContext(ctx, d)
+}
+
+// GoToPage returns the given page of the results the given paged response
+// belongs to. It's equivalent to p.GoToPage(d, number)
+func (d *DiscoveryClient) GoToPage(
+	p *PagedResponse,
+	number int,
+) (*PagedResponse, error) {
+	return p.GoToPageContext(context.Background(), d, number)
+}
+
+// GoToPageContext returns the given page of the results the given paged
+// response belongs to. The request is bound to the given context
+func (d *DiscoveryClient) GoToPageContext(
+	ctx context.Context,
+	p *PagedResponse,
+	number int,
+) (*PagedResponse, error) {
+	return p.GoToPageContext(ctx, d, number)
+}
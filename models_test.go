@@ -0,0 +1,369 @@
+package discoverygo
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testEvent returns testEventJson decoded as it would be in
+// EmbeddedResponse.Events
+func testEvent(t *testing.T) map[string]any {
+	t.Helper()
+	var event map[string]any
+	if err := json.Unmarshal([]byte(testEventJson), &event); err != nil {
+		t.Fatalf("Error decoding event json: %v", err)
+	}
+	return event
+}
+
+func TestEventPriceRanges(t *testing.T) {
+	priceRanges := EventPriceRanges(testEvent(t))
+	expected := PriceRange{Type: "standard", Currency: "USD", Min: 80, Max: 80}
+	if len(priceRanges) != 1 || priceRanges[0] != expected {
+		t.Errorf("Expected %+v, got: %+v", expected, priceRanges)
+	}
+}
+
+func TestEventPriceRangesPartial(t *testing.T) {
+	event := map[string]any{
+		"priceRanges": []any{
+			map[string]any{"currency": "USD", "min": 10.5},
+			"not an object",
+		},
+	}
+	priceRanges := EventPriceRanges(event)
+	expected := PriceRange{Currency: "USD", Min: 10.5}
+	if len(priceRanges) != 1 || priceRanges[0] != expected {
+		t.Errorf("Expected %+v, got: %+v", expected, priceRanges)
+	}
+	if EventPriceRanges(map[string]any{}) != nil {
+		t.Errorf("Expected nil for an event without price ranges")
+	}
+}
+
+func TestMaxReachablePage(t *testing.T) {
+	tests := []struct {
+		page     Page
+		expected int
+	}{
+		{Page{Size: 200, TotalPages: 25}, 4},
+		{Page{Size: 20, TotalPages: 100}, 49},
+		{Page{Size: 30, TotalPages: 100}, 33},
+		{Page{Size: 20, TotalPages: 3}, 2},
+		{Page{Size: 20, TotalPages: 0}, 0},
+	}
+	for _, test := range tests {
+		p := &PagedResponse{Page: test.page}
+		if last := p.MaxReachablePage(nil); last != test.expected {
+			t.Errorf("Expected %v, got: %v (%+v)", test.expected, last, test.page)
+		}
+		if last := p.MaxReachablePage(&DiscoveryClient{}); last != test.expected {
+			t.Errorf("Expected %v, got: %v (%+v)", test.expected, last, test.page)
+		}
+	}
+	p := &PagedResponse{Page: Page{Size: 200, TotalPages: 25}}
+	client := &DiscoveryClient{MaxResultDepth: 2000}
+	if last := p.MaxReachablePage(client); last != 9 {
+		t.Errorf("Expected %v, got: %v", 9, last)
+	}
+}
+
+func TestHasNextHasPrev(t *testing.T) {
+	p := &PagedResponse{Links: Links{Next: Link{Href: "/events?page=1"}}}
+	if !p.HasNext() {
+		t.Errorf("Expected next page")
+	}
+	if p.HasPrev() {
+		t.Errorf("Expected no previous page")
+	}
+}
+
+func TestVenueLocation(t *testing.T) {
+	var venue map[string]any
+	if err := json.Unmarshal([]byte(testVenueJson), &venue); err != nil {
+		t.Fatalf("Error decoding venue json: %v", err)
+	}
+	location, ok := VenueLocation(venue)
+	expected := Location{Latitude: 40.7497062, Longitude: -73.9916006}
+	if !ok || location != expected {
+		t.Errorf("Expected %+v, got: %+v (%v)", expected, location, ok)
+	}
+}
+
+func TestVenueLocationMissing(t *testing.T) {
+	venues := []map[string]any{
+		{},
+		{"location": "nowhere"},
+		{"location": map[string]any{"latitude": "40.7"}},
+		{"location": map[string]any{"latitude": "north", "longitude": "-73.9"}},
+	}
+	for _, venue := range venues {
+		if location, ok := VenueLocation(venue); ok {
+			t.Errorf("Expected no location for %v, got: %+v", venue, location)
+		}
+	}
+}
+
+func TestBestImage(t *testing.T) {
+	event := testEvent(t)
+	tests := map[int]string{
+		0:    "RECOMENDATION_16_9",
+		300:  "ARTIST_PAGE_3_2",
+		600:  "RETINA_PORTRAIT_16_9",
+		1100: "RETINA_LANDSCAPE_16_9",
+		5000: "TABLET_LANDSCAPE_LARGE_16_9",
+	}
+	for minWidth, expected := range tests {
+		url, ok := BestImage(event, minWidth)
+		if !ok || !strings.Contains(url, expected) {
+			t.Errorf("%d: Expected %v, got: %v", minWidth, expected, url)
+		}
+	}
+}
+
+func TestBestImageMissing(t *testing.T) {
+	items := []map[string]any{
+		{},
+		{"images": "none"},
+		{"images": []any{map[string]any{"width": 100.0}}},
+	}
+	for _, item := range items {
+		if url, ok := BestImage(item, 100); ok {
+			t.Errorf("Expected no image for %v, got: %v", item, url)
+		}
+	}
+}
+
+func TestImagesTyped(t *testing.T) {
+	var event Event
+	if err := json.Unmarshal([]byte(testEventJson), &event); err != nil {
+		t.Fatalf("Error decoding event json: %v", err)
+	}
+	if len(event.Images) != len(Images(testEvent(t))) {
+		t.Errorf(
+			"Expected %v, got: %v",
+			len(Images(testEvent(t))),
+			len(event.Images),
+		)
+	}
+	image, ok := BestImageOf(event.Images, 1000)
+	if !ok || image.Width != 1024 {
+		t.Errorf("Expected %v, got: %v", 1024, image.Width)
+	}
+}
+
+func TestEventSales(t *testing.T) {
+	sales := EventSales(testEvent(t))
+	start := time.Date(2016, 3, 18, 14, 0, 0, 0, time.UTC)
+	end := time.Date(2016, 7, 27, 21, 30, 0, 0, time.UTC)
+	if !sales.Public.StartDateTime.Equal(start) {
+		t.Errorf("Expected %v, got: %v", start, sales.Public.StartDateTime)
+	}
+	if !sales.Public.EndDateTime.Equal(end) {
+		t.Errorf("Expected %v, got: %v", end, sales.Public.EndDateTime)
+	}
+	if !sales.Public.StartKnown() {
+		t.Errorf("Expected start to be known")
+	}
+	if !sales.Public.OnSaleAt(start.Add(time.Hour)) {
+		t.Errorf("Expected to be on sale after start")
+	}
+	if sales.Public.OnSaleAt(start.Add(-time.Hour)) {
+		t.Errorf("Expected not to be on sale before start")
+	}
+	if sales.Public.OnSaleAt(end) {
+		t.Errorf("Expected not to be on sale at end")
+	}
+
+	var event Event
+	if err := json.Unmarshal([]byte(testEventJson), &event); err != nil {
+		t.Fatalf("Error decoding event json: %v", err)
+	}
+	if event.Sales == nil || !event.Sales.Public.StartDateTime.Equal(start) {
+		t.Errorf("Expected %v, got: %+v", start, event.Sales)
+	}
+}
+
+func TestEventSalesPresalesAndTBA(t *testing.T) {
+	event := map[string]any{
+		"sales": map[string]any{
+			"public": map[string]any{
+				"startDateTime": "2024-03-01T15:00:00Z",
+				"startTBA":      true,
+			},
+			"presales": []any{
+				map[string]any{
+					"name":          "Fan Club Presale",
+					"startDateTime": "2024-02-27T15:00:00Z",
+					"endDateTime":   "2024-02-29T03:00:00Z",
+				},
+			},
+		},
+	}
+	sales := EventSales(event)
+	if sales.Public.StartKnown() {
+		t.Errorf("Expected start not to be known when TBA")
+	}
+	if sales.Public.OnSaleAt(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected not to be on sale when start is TBA")
+	}
+	if len(sales.Presales) != 1 || sales.Presales[0].Name != "Fan Club Presale" {
+		t.Fatalf("Expected one presale, got: %+v", sales.Presales)
+	}
+	start := time.Date(2024, 2, 27, 15, 0, 0, 0, time.UTC)
+	if !sales.Presales[0].StartDateTime.Equal(start) {
+		t.Errorf("Expected %v, got: %v", start, sales.Presales[0].StartDateTime)
+	}
+	if sales := EventSales(map[string]any{}); sales.Public.StartKnown() {
+		t.Errorf("Expected no sales for an event without them, got: %+v", sales)
+	}
+}
+
+func TestEmbeddedResponseExtra(t *testing.T) {
+	data := `{
+  "events": [{"id": "1"}],
+  "products": [{"id": "p1", "name": "Parking"}],
+  "count": 3
+}`
+	var embedded EmbeddedResponse
+	if err := json.Unmarshal([]byte(data), &embedded); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(embedded.Events) != 1 {
+		t.Errorf("Expected %v, got: %v", 1, len(embedded.Events))
+	}
+	products := embedded.Extra["products"]
+	if len(products) != 1 || products[0]["name"] != "Parking" {
+		t.Errorf("Expected one product, got: %v", embedded.Extra)
+	}
+	if _, ok := embedded.Extra["events"]; ok {
+		t.Errorf("Expected events not to be in Extra")
+	}
+	if _, ok := embedded.Extra["count"]; ok {
+		t.Errorf("Expected non-array values to be ignored")
+	}
+
+	encoded, err := json.Marshal(embedded)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var roundTrip EmbeddedResponse
+	if err := json.Unmarshal(encoded, &roundTrip); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(roundTrip.Events) != 1 || len(roundTrip.Extra["products"]) != 1 {
+		t.Errorf("Expected events and products to round trip, got: %s", encoded)
+	}
+}
+
+func TestEventDistance(t *testing.T) {
+	event := map[string]any{"distance": 3.21, "units": "MILES"}
+	distance, units, ok := EventDistance(event)
+	if !ok || distance != 3.21 || units != "MILES" {
+		t.Errorf("Expected %v %v, got: %v %v (%v)", 3.21, "MILES", distance, units, ok)
+	}
+	if _, _, ok := EventDistance(testEvent(t)); ok {
+		t.Errorf("Expected no distance for an event without one")
+	}
+}
+
+func TestEventVenues(t *testing.T) {
+	venues := EventVenues(testEvent(t))
+	if len(venues) != 1 {
+		t.Fatalf("Expected 1 venue, got: %d", len(venues))
+	}
+	if name := venues[0]["name"]; name != "Madison Square Garden" {
+		t.Errorf("Expected %v, got: %v", "Madison Square Garden", name)
+	}
+	events := []map[string]any{
+		{},
+		{"_embedded": "none"},
+		{"_embedded": map[string]any{"attractions": []any{}}},
+	}
+	for _, event := range events {
+		if venues := EventVenues(event); venues != nil {
+			t.Errorf("Expected no venues for %v, got: %v", event, venues)
+		}
+	}
+}
+
+func TestExpandLink(t *testing.T) {
+	vars := map[string]string{
+		"id":     "G5diZfkn0B-bh",
+		"locale": "en-us",
+		"page":   "2",
+		"sort":   "name,asc",
+	}
+	tests := map[string]string{
+		"/events/{id}":                         "/events/G5diZfkn0B-bh",
+		"/events/{id}{?locale}":                "/events/G5diZfkn0B-bh?locale=en-us",
+		"/events?keyword=foo{&page,size,sort}": "/events?keyword=foo&page=2&sort=name%2Casc",
+		"/events{?size}":                       "/events",
+		"/events":                              "/events",
+	}
+	for href, expected := range tests {
+		expanded, err := ExpandLink(Link{Href: href, Templated: true}, vars)
+		if err != nil {
+			t.Errorf("%s: Unexpected error: %v", href, err)
+		}
+		if expanded != expected {
+			t.Errorf("%s: Expected %v, got: %v", href, expected, expanded)
+		}
+	}
+}
+
+func TestExpandLinkErrors(t *testing.T) {
+	hrefs := []string{
+		"/events/{id}",
+		"/events/{+path}",
+		"/events{#fragment}",
+		"/events/{id:3}",
+		"/events{?locale",
+	}
+	for _, href := range hrefs {
+		_, err := ExpandLink(Link{Href: href, Templated: true}, nil)
+		if !errors.Is(err, ErrTemplatedLink) {
+			t.Errorf("%s: Expected %v, got: %v", href, ErrTemplatedLink, err)
+		}
+	}
+}
+
+func TestStartTime(t *testing.T) {
+	start, ok := StartTime(testEvent(t))
+	expected := time.Date(2016, 7, 27, 23, 30, 0, 0, time.UTC)
+	if !ok || !start.Equal(expected) {
+		t.Errorf("Expected %v, got: %v (%v)", expected, start, ok)
+	}
+	if name := start.Location().String(); name != "America/New_York" {
+		t.Errorf("Expected %v, got: %v", "America/New_York", name)
+	}
+}
+
+func TestStartTimeLocal(t *testing.T) {
+	dates := Dates{
+		Start:    StartDate{LocalDate: "2016-07-27", LocalTime: "19:30:00"},
+		Timezone: "America/New_York",
+	}
+	start, ok := dates.StartTime()
+	expected := time.Date(2016, 7, 27, 23, 30, 0, 0, time.UTC)
+	if !ok || !start.Equal(expected) {
+		t.Errorf("Expected %v, got: %v (%v)", expected, start, ok)
+	}
+
+	unknown := []Dates{
+		{},
+		{Start: StartDate{LocalDate: "2016-07-27", LocalTime: "19:30:00"}},
+		{Start: StartDate{LocalDate: "2016-07-27"}, Timezone: "America/New_York"},
+		{Start: StartDate{DateTime: "2016-07-27T23:30:00Z", DateTBA: true}},
+		{Start: StartDate{DateTime: "2016-07-27T23:30:00Z", DateTBD: true}},
+		{Start: StartDate{DateTime: "2016-07-27T23:30:00Z", NoSpecificTime: true}},
+	}
+	for _, dates := range unknown {
+		if start, ok := dates.StartTime(); ok {
+			t.Errorf("Expected no start time for %+v, got: %v", dates, start)
+		}
+	}
+}
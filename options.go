@@ -0,0 +1,235 @@
+package discoverygo
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Option configures a DiscoveryClient created with NewDiscoveryClient
+type Option func(*DiscoveryClient) error
+
+// WithBaseURL overrides the base URL of the Discovery API, e.g. to point
+// the client at a mock server or a proxy. The URL must be an absolute http
+// or https URL. Trailing slashes are removed
+func WithBaseURL(baseUrl string) Option {
+	return func(d *DiscoveryClient) error {
+		u, err := url.Parse(baseUrl)
+		if err != nil {
+			return err
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf(
+				"Invalid base URL %q: scheme must be http or https",
+				baseUrl,
+			)
+		}
+		if u.Host == "" {
+			return fmt.Errorf("Invalid base URL %q: missing host", baseUrl)
+		}
+		if u.RawQuery != "" || u.Fragment != "" {
+			return fmt.Errorf(
+				"Invalid base URL %q: must not have a query or fragment",
+				baseUrl,
+			)
+		}
+		u.Path = strings.TrimRight(u.Path, "/")
+		u.RawPath = ""
+		d.ApiUrl = *u
+		return nil
+	}
+}
+
+// WithHTTPClient sets the HTTP client requests are sent with
+func WithHTTPClient(client *http.Client) Option {
+	return func(d *DiscoveryClient) error {
+		d.HTTPClient = client
+		return nil
+	}
+}
+
+// WithTransport sets the transport requests are sent with, e.g. one from
+// NewTransport with its connection pool tuned. It applies to the client
+// given to WithHTTPClient regardless of the order of the options, without
+// modifying it
+func WithTransport(transport http.RoundTripper) Option {
+	return func(d *DiscoveryClient) error {
+		if transport == nil {
+			return fmt.Errorf("Invalid transport: %v", transport)
+		}
+		d.transport = transport
+		return nil
+	}
+}
+
+// WithAuthMode sets how the API key is sent with requests
+func WithAuthMode(mode AuthMode) Option {
+	return func(d *DiscoveryClient) error {
+		d.AuthMode = mode
+		return nil
+	}
+}
+
+// WithMaxRetries sets the number of times a rate-limited request is retried
+func WithMaxRetries(n int) Option {
+	return func(d *DiscoveryClient) error {
+		d.MaxRetries = n
+		return nil
+	}
+}
+
+// WithRetryPolicy sets which responses are retried, and the backoff
+// between attempts. Retries are still limited by MaxRetries
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(d *DiscoveryClient) error {
+		d.RetryPolicy = &policy
+		return nil
+	}
+}
+
+// WithLogger sets the logger requests are logged with
+func WithLogger(logger *slog.Logger) Option {
+	return func(d *DiscoveryClient) error {
+		d.Logger = logger
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with requests
+func WithUserAgent(userAgent string) Option {
+	return func(d *DiscoveryClient) error {
+		d.UserAgent = userAgent
+		return nil
+	}
+}
+
+// WithDefaultLocale sets the locale sent with requests that don't
+// specify one
+func WithDefaultLocale(locale string) Option {
+	return func(d *DiscoveryClient) error {
+		d.DefaultLocale = locale
+		return nil
+	}
+}
+
+// WithCache sets the cache used to store successful responses, and how
+// long they're kept if the response doesn't say otherwise
+func WithCache(cache Cache, ttl time.Duration) Option {
+	return func(d *DiscoveryClient) error {
+		d.Cache = cache
+		d.CacheTTL = ttl
+		return nil
+	}
+}
+
+// WithKeywordEscaping escapes reserved search syntax characters in the
+// keyword parameter of each search. See EscapeKeyword
+func WithKeywordEscaping() Option {
+	return func(d *DiscoveryClient) error {
+		d.EscapeKeywords = true
+		return nil
+	}
+}
+
+// WithConcurrency sets the maximum number of requests AllEvents (pages) or
+// GetEvents (events) has in flight at once
+func WithConcurrency(n int) Option {
+	return func(d *DiscoveryClient) error {
+		if n < 1 {
+			return fmt.Errorf("Invalid concurrency: %d", n)
+		}
+		d.Concurrency = n
+		return nil
+	}
+}
+
+// WithTimeout sets a time limit for each request, as http.Client.Timeout
+// does. It applies to the client given to WithHTTPClient regardless of the
+// order of the options, without modifying it
+func WithTimeout(timeout time.Duration) Option {
+	return func(d *DiscoveryClient) error {
+		if timeout <= 0 {
+			return fmt.Errorf("Invalid timeout: %v", timeout)
+		}
+		d.timeout = timeout
+		return nil
+	}
+}
+
+// WithoutAPIKey stops the client sending an API key with requests, for
+// when a proxy in front of the API adds it. NewDiscoveryClient can then be
+// given an empty API key
+func WithoutAPIKey() Option {
+	return func(d *DiscoveryClient) error {
+		d.DisableAPIKey = true
+		return nil
+	}
+}
+
+// WithoutRedirects stops the client following redirects
+func WithoutRedirects() Option {
+	return func(d *DiscoveryClient) error {
+		d.DisableRedirects = true
+		return nil
+	}
+}
+
+// WithETags sends conditional requests using the ETag of the previous
+// identical request. See DiscoveryClient.UseETags
+func WithETags() Option {
+	return func(d *DiscoveryClient) error {
+		d.UseETags = true
+		return nil
+	}
+}
+
+// WithStrictDecoding rejects response bodies with data after their JSON
+// value. See DiscoveryClient.StrictDecoding
+func WithStrictDecoding() Option {
+	return func(d *DiscoveryClient) error {
+		d.StrictDecoding = true
+		return nil
+	}
+}
+
+// WithMaxResultDepth sets the number of results of a search that can be
+// paged through. See DiscoveryClient.MaxResultDepth
+func WithMaxResultDepth(depth int) Option {
+	return func(d *DiscoveryClient) error {
+		if depth < 1 {
+			return fmt.Errorf("Invalid max result depth: %d", depth)
+		}
+		d.MaxResultDepth = depth
+		return nil
+	}
+}
+
+// WithErrorTiming makes failed requests return a *RequestError with the
+// URL and duration of the request. See DiscoveryClient.ErrorTiming
+func WithErrorTiming() Option {
+	return func(d *DiscoveryClient) error {
+		d.ErrorTiming = true
+		return nil
+	}
+}
+
+// WithEventDeduplication makes AllEvents and StreamEvents drop events with
+// the same key as an earlier one. If key is nil, DefaultEventKey is used
+func WithEventDeduplication(key func(event map[string]any) string) Option {
+	return func(d *DiscoveryClient) error {
+		d.DeduplicateEvents = true
+		d.EventKey = key
+		return nil
+	}
+}
+
+// WithHooks sets the hooks called around each HTTP request
+func WithHooks(hooks Hooks) Option {
+	return func(d *DiscoveryClient) error {
+		d.Hooks = &hooks
+		return nil
+	}
+}
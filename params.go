@@ -0,0 +1,448 @@
+package discoverygo
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MaxSize is the largest page size the Discovery API accepts
+const MaxSize = 200
+
+// Sort orders, for QueryParams.Sort
+const (
+	SortNameAsc            = "name,asc"
+	SortNameDesc           = "name,desc"
+	SortDateAsc            = "date,asc"
+	SortDateDesc           = "date,desc"
+	SortRelevanceAsc       = "relevance,asc"
+	SortRelevanceDesc      = "relevance,desc"
+	SortDistanceAsc        = "distance,asc"
+	SortNameDateAsc        = "name,date,asc"
+	SortNameDateDesc       = "name,date,desc"
+	SortDateNameAsc        = "date,name,asc"
+	SortDateNameDesc       = "date,name,desc"
+	SortDistanceDateAsc    = "distance,date,asc"
+	SortOnSaleStartDateAsc = "onSaleStartDate,asc"
+	SortIDAsc              = "id,asc"
+	SortVenueNameAsc       = "venueName,asc"
+	SortVenueNameDesc      = "venueName,desc"
+	SortRandom             = "random"
+)
+
+// sorts are the sort orders the Discovery API accepts
+var sorts = map[string]bool{
+	SortNameAsc:            true,
+	SortNameDesc:           true,
+	SortDateAsc:            true,
+	SortDateDesc:           true,
+	SortRelevanceAsc:       true,
+	SortRelevanceDesc:      true,
+	SortDistanceAsc:        true,
+	SortNameDateAsc:        true,
+	SortNameDateDesc:       true,
+	SortDateNameAsc:        true,
+	SortDateNameDesc:       true,
+	SortDistanceDateAsc:    true,
+	SortOnSaleStartDateAsc: true,
+	SortIDAsc:              true,
+	SortVenueNameAsc:       true,
+	SortVenueNameDesc:      true,
+	SortRandom:             true,
+}
+
+// IncludeFilter is whether results of a kind are included in a search,
+// for the QueryParams.Include* fields
+type IncludeFilter string
+
+// Include filters. IncludeSpellcheck only accepts IncludeYes or IncludeNo
+const (
+	IncludeYes  IncludeFilter = "yes"
+	IncludeNo   IncludeFilter = "no"
+	IncludeOnly IncludeFilter = "only"
+)
+
+// validate returns an error if the filter isn't empty or one of the
+// given values, naming the parameter it was set for
+func (f IncludeFilter) validate(name string, allowed ...IncludeFilter) error {
+	if f == "" || slices.Contains(allowed, f) {
+		return nil
+	}
+	return fmt.Errorf("Invalid %s %q: must be one of %q", name, f, allowed)
+}
+
+// Distance units, for QueryParams.Unit
+const (
+	UnitMiles = "miles"
+	UnitKm    = "km"
+)
+
+// DefaultUnit is the unit sent when QueryParams.Radius is set without a
+// Unit, matching the API's own default
+const DefaultUnit = UnitMiles
+
+// Country codes for common markets, for QueryParams.CountryCode. Note that
+// the United Kingdom is "GB", not "UK"
+const (
+	CountryUS = "US"
+	CountryCA = "CA"
+	CountryMX = "MX"
+	CountryGB = "GB"
+	CountryIE = "IE"
+	CountryDE = "DE"
+	CountryFR = "FR"
+	CountryES = "ES"
+	CountryNL = "NL"
+	CountryAU = "AU"
+	CountryNZ = "NZ"
+)
+
+// countryCodes are the ISO 3166 country codes supported by the
+// Discovery API, and the names of their countries
+// See: https://developer.ticketmaster.com/products-and-docs/apis/discovery-api/v2/#supported-country-codes
+var countryCodes = map[string]string{
+	"US": "United States Of America",
+	"AD": "Andorra",
+	"AI": "Anguilla",
+	"AR": "Argentina",
+	"AU": "Australia",
+	"AT": "Austria",
+	"AZ": "Azerbaijan",
+	"BS": "Bahamas",
+	"BH": "Bahrain",
+	"BB": "Barbados",
+	"BE": "Belgium",
+	"BM": "Bermuda",
+	"BR": "Brazil",
+	"BG": "Bulgaria",
+	"CA": "Canada",
+	"CL": "Chile",
+	"CN": "China",
+	"CO": "Colombia",
+	"CR": "Costa Rica",
+	"HR": "Croatia",
+	"CY": "Cyprus",
+	"CZ": "Czech Republic",
+	"DK": "Denmark",
+	"DO": "Dominican Republic",
+	"EC": "Ecuador",
+	"EE": "Estonia",
+	"FO": "Faroe Islands",
+	"FI": "Finland",
+	"FR": "France",
+	"GE": "Georgia",
+	"DE": "Germany",
+	"GH": "Ghana",
+	"GI": "Gibraltar",
+	"GB": "Great Britain",
+	"GR": "Greece",
+	"HK": "Hong Kong",
+	"HU": "Hungary",
+	"IS": "Iceland",
+	"IN": "India",
+	"IE": "Ireland",
+	"IL": "Israel",
+	"IT": "Italy",
+	"JM": "Jamaica",
+	"JP": "Japan",
+	"KR": "Korea, Republic of",
+	"LV": "Latvia",
+	"LB": "Lebanon",
+	"LT": "Lithuania",
+	"LU": "Luxembourg",
+	"MY": "Malaysia",
+	"MT": "Malta",
+	"MX": "Mexico",
+	"MC": "Monaco",
+	"ME": "Montenegro",
+	"MA": "Morocco",
+	"NL": "Netherlands",
+	"AN": "Netherlands Antilles",
+	"NZ": "New Zealand",
+	"ND": "Northern Ireland",
+	"NO": "Norway",
+	"PE": "Peru",
+	"PL": "Poland",
+	"PT": "Portugal",
+	"RO": "Romania",
+	"RU": "Russian Federation",
+	"LC": "Saint Lucia",
+	"SA": "Saudi Arabia",
+	"RS": "Serbia",
+	"SG": "Singapore",
+	"SK": "Slovakia",
+	"SI": "Slovenia",
+	"ZA": "South Africa",
+	"ES": "Spain",
+	"SE": "Sweden",
+	"CH": "Switzerland",
+	"TW": "Taiwan",
+	"TH": "Thailand",
+	"TT": "Trinidad and Tobago",
+	"TR": "Turkey",
+	"UA": "Ukraine",
+	"AE": "United Arab Emirates",
+	"UY": "Uruguay",
+	"VE": "Venezuela",
+}
+
+// IsSupportedCountryCode returns true if the Discovery API supports the
+// given country code
+func IsSupportedCountryCode(code string) bool {
+	_, ok := countryCodes[code]
+	return ok
+}
+
+// Validate checks the query parameters for values the Discovery API is
+// known to reject
+func (q QueryParams) Validate() error {
+	if q.Size < 0 || q.Size > MaxSize {
+		return fmt.Errorf(
+			"Invalid size %d: must be between 0 and %d",
+			q.Size,
+			MaxSize,
+		)
+	}
+	if q.Page < 0 {
+		return fmt.Errorf("Invalid page %d: must not be negative", q.Page)
+	}
+	if q.Sort != "" && !sorts[q.Sort] {
+		return fmt.Errorf(
+			"Invalid sort %q: expected a value such as %q",
+			q.Sort,
+			SortDateAsc,
+		)
+	}
+	all := []IncludeFilter{IncludeYes, IncludeNo, IncludeOnly}
+	filters := []struct {
+		name    string
+		value   IncludeFilter
+		allowed []IncludeFilter
+	}{
+		{"includeTest", q.IncludeTest, all},
+		{"includeTBA", q.IncludeTBA, all},
+		{"includeTBD", q.IncludeTBD, all},
+		{"includeSpellcheck", q.IncludeSpellcheck, all[:2]},
+		{"includeFamily", q.IncludeFamily, all},
+	}
+	for _, f := range filters {
+		if err := f.value.validate(f.name, f.allowed...); err != nil {
+			return err
+		}
+	}
+	if q.Unit != "" && q.Unit != UnitMiles && q.Unit != UnitKm {
+		return fmt.Errorf(
+			"Invalid unit %q: must be %q or %q",
+			q.Unit,
+			UnitMiles,
+			UnitKm,
+		)
+	}
+	if q.CountryCode != "" && !IsSupportedCountryCode(q.CountryCode) {
+		if q.CountryCode == "UK" {
+			return fmt.Errorf(
+				"Unsupported country code %q: did you mean %q?",
+				q.CountryCode,
+				CountryGB,
+			)
+		}
+		return fmt.Errorf("Unsupported country code %q", q.CountryCode)
+	}
+	if err := q.validateDateRange(); err != nil {
+		return err
+	}
+	if err := q.validateRadius(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Values returns the query parameters as URL values, omitting any that are
+// empty. Each value of a slice field is added as its own parameter. If
+// Radius is set without a Unit, DefaultUnit is used. Extra parameters are
+// added for keys not already set
+func (q QueryParams) Values() url.Values {
+	values := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	setInt := func(key string, value int) {
+		if value != 0 {
+			values.Set(key, strconv.Itoa(value))
+		}
+	}
+	add := func(key string, list []string) {
+		for _, value := range list {
+			if value != "" {
+				values.Add(key, value)
+			}
+		}
+	}
+	set("id", q.Id)
+	set("sort", q.Sort)
+	setInt("page", q.Page)
+	setInt("size", q.Size)
+	set("locale", q.Locale)
+	set("keyword", q.Keyword)
+	set("includeTest", string(q.IncludeTest))
+	set("includeTBA", string(q.IncludeTBA))
+	set("includeTBD", string(q.IncludeTBD))
+	set("includeSpellcheck", string(q.IncludeSpellcheck))
+	set("includeFamily", string(q.IncludeFamily))
+	set("includeLicensedContent", q.IncludeLicensedContent)
+	add("venueId", q.VenueID)
+	set("startDateTime", q.StartDateTime)
+	set("endDateTime", q.EndDateTime)
+	set("countryCode", q.CountryCode)
+	set("stateCode", q.StateCode)
+	set("postalCode", q.PostalCode)
+	add("attractionId", q.AttractionID)
+	add("segmentId", q.SegmentID)
+	set("segmentName", q.SegmentName)
+	add("genreId", q.GenreID)
+	add("subGenreId", q.SubGenreID)
+	add("classificationId", q.ClassificationID)
+	set("classificationName", q.ClassificationName)
+	add("marketId", q.MarketID)
+	set("promoterId", q.PromoterID)
+	set("dmaId", q.DmaID)
+	set("latlong", q.LatLong)
+	set("geoPoint", q.GeoPoint)
+	set("radius", q.Radius)
+	set("unit", q.Unit)
+	if q.Radius != "" && q.Unit == "" {
+		values.Set("unit", DefaultUnit)
+	}
+	set("source", q.Source)
+	add("resource", q.Resource)
+	set("preferredCountry", q.PreferredCountry)
+	for key, list := range q.Extra {
+		if key == "apikey" || values.Has(key) || len(list) == 0 {
+			continue
+		}
+		values[key] = slices.Clone(list)
+	}
+	return values
+}
+
+// Clone returns a copy of the query parameters that shares no slices with
+// the original, so either can be modified safely
+func (q QueryParams) Clone() QueryParams {
+	q.VenueID = slices.Clone(q.VenueID)
+	q.AttractionID = slices.Clone(q.AttractionID)
+	q.SegmentID = slices.Clone(q.SegmentID)
+	q.GenreID = slices.Clone(q.GenreID)
+	q.SubGenreID = slices.Clone(q.SubGenreID)
+	q.ClassificationID = slices.Clone(q.ClassificationID)
+	q.MarketID = slices.Clone(q.MarketID)
+	q.Resource = slices.Clone(q.Resource)
+	if q.Extra != nil {
+		extra := make(url.Values, len(q.Extra))
+		for key, list := range q.Extra {
+			extra[key] = slices.Clone(list)
+		}
+		q.Extra = extra
+	}
+	return q
+}
+
+// WithPage returns a copy of the query parameters (see Clone) for the given
+// page number
+func (q QueryParams) WithPage(page int) QueryParams {
+	q = q.Clone()
+	q.Page = page
+	return q
+}
+
+// QueryParamsFromValues returns the query parameters in the given URL
+// values, e.g. those of an incoming request to be passed on to a search.
+// It's the inverse of QueryParams.Values. Unknown keys are ignored, as are
+// page and size if they aren't integers. The parameters aren't validated
+func QueryParamsFromValues(v url.Values) QueryParams {
+	var q QueryParams
+	q.Id = v.Get("id")
+	q.Sort = v.Get("sort")
+	q.Page, _ = strconv.Atoi(v.Get("page"))
+	q.Size, _ = strconv.Atoi(v.Get("size"))
+	q.Locale = v.Get("locale")
+	q.Keyword = v.Get("keyword")
+	q.IncludeTest = IncludeFilter(v.Get("includeTest"))
+	q.IncludeTBA = IncludeFilter(v.Get("includeTBA"))
+	q.IncludeTBD = IncludeFilter(v.Get("includeTBD"))
+	q.IncludeSpellcheck = IncludeFilter(v.Get("includeSpellcheck"))
+	q.IncludeFamily = IncludeFilter(v.Get("includeFamily"))
+	q.IncludeLicensedContent = v.Get("includeLicensedContent")
+	q.VenueID = slices.Clone(v["venueId"])
+	q.StartDateTime = v.Get("startDateTime")
+	q.EndDateTime = v.Get("endDateTime")
+	q.CountryCode = v.Get("countryCode")
+	q.StateCode = v.Get("stateCode")
+	q.PostalCode = v.Get("postalCode")
+	q.AttractionID = slices.Clone(v["attractionId"])
+	q.SegmentID = slices.Clone(v["segmentId"])
+	q.SegmentName = v.Get("segmentName")
+	q.GenreID = slices.Clone(v["genreId"])
+	q.SubGenreID = slices.Clone(v["subGenreId"])
+	q.ClassificationID = slices.Clone(v["classificationId"])
+	q.ClassificationName = v.Get("classificationName")
+	q.MarketID = slices.Clone(v["marketId"])
+	q.PromoterID = v.Get("promoterId")
+	q.DmaID = v.Get("dmaId")
+	q.LatLong = v.Get("latlong")
+	q.GeoPoint = v.Get("geoPoint")
+	q.Radius = v.Get("radius")
+	q.Unit = v.Get("unit")
+	q.Source = v.Get("source")
+	q.Resource = slices.Clone(v["resource"])
+	q.PreferredCountry = v.Get("preferredCountry")
+	return q
+}
+
+// Encode returns the query parameters as a URL-encoded query string, e.g.
+// to store a search and replay it later with ParseQueryParams. Keys are
+// sorted, and the API key is never included
+func (q QueryParams) Encode() string {
+	return q.Values().Encode()
+}
+
+// ParseQueryParams returns the query parameters in a query string from
+// Encode (or a URL's RawQuery), as QueryParamsFromValues does, except that
+// unknown keys are kept in Extra (other than apikey) so they're sent again
+func ParseQueryParams(query string) (QueryParams, error) {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return QueryParams{}, err
+	}
+	q := QueryParamsFromValues(values)
+	known := queryParamKeys()
+	for key, list := range values {
+		if known[key] || key == "apikey" {
+			continue
+		}
+		if q.Extra == nil {
+			q.Extra = url.Values{}
+		}
+		q.Extra[key] = list
+	}
+	return q, nil
+}
+
+// queryParamKeys returns the query parameter names of the QueryParams
+// fields, from their JSON tags
+var queryParamKeys = sync.OnceValue(func() map[string]bool {
+	keys := map[string]bool{}
+	t := reflect.TypeOf(QueryParams{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+})
@@ -0,0 +1,741 @@
+package discoverygo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateSize(t *testing.T) {
+	valid := []int{0, 20, 200}
+	for _, size := range valid {
+		if err := (QueryParams{Size: size}).Validate(); err != nil {
+			t.Errorf("%d: Unexpected error: %v", size, err)
+		}
+	}
+	invalid := []int{201, -1}
+	for _, size := range invalid {
+		if err := (QueryParams{Size: size}).Validate(); err == nil {
+			t.Errorf("%d: Expected an error", size)
+		}
+	}
+}
+
+func TestValidatePage(t *testing.T) {
+	if err := (QueryParams{Page: -1}).Validate(); err == nil {
+		t.Errorf("Expected an error for page -1")
+	}
+}
+
+func TestUpdateURLPagination(t *testing.T) {
+	apiUrl, _ := url.Parse(DiscoveryApiUrl)
+	u, err := QueryParams{Size: 50, Page: 2}.UpdateURL(*apiUrl, "12345")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	q := u.Query()
+	if q.Get("size") != "50" {
+		t.Errorf("Expected %v, got: %v", "50", q.Get("size"))
+	}
+	if q.Get("page") != "2" {
+		t.Errorf("Expected %v, got: %v", "2", q.Get("page"))
+	}
+	u, _ = QueryParams{}.UpdateURL(*apiUrl, "12345")
+	if u.Query().Has("size") || u.Query().Has("page") {
+		t.Errorf("Expected no size or page, got: %v", u.RawQuery)
+	}
+}
+
+func TestUpdateURLValidates(t *testing.T) {
+	apiUrl, _ := url.Parse(DiscoveryApiUrl)
+	_, err := QueryParams{Size: 500}.UpdateURL(*apiUrl, "12345")
+	if err == nil {
+		t.Errorf("Expected an error for size 500")
+	}
+}
+
+func TestUpdateURLRepeatableParams(t *testing.T) {
+	apiUrl, _ := url.Parse(DiscoveryApiUrl)
+	u, err := QueryParams{
+		ClassificationID: []string{"a", "b"},
+		VenueID:          []string{"KovZpZA7AAEA"},
+	}.UpdateURL(*apiUrl, "12345")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	q := u.Query()
+	if fmt.Sprint(q["classificationId"]) != "[a b]" {
+		t.Errorf("Expected %v, got: %v", "[a b]", q["classificationId"])
+	}
+	if fmt.Sprint(q["venueId"]) != "[KovZpZA7AAEA]" {
+		t.Errorf("Expected %v, got: %v", "[KovZpZA7AAEA]", q["venueId"])
+	}
+}
+
+func TestGeohash(t *testing.T) {
+	tests := []struct {
+		lat, long float64
+		precision int
+		expected  string
+	}{
+		{57.64911, 10.40744, 11, "u4pruydqqvj"},
+		{40.7497062, -73.9916006, 9, "dr5ru62bn"},
+		{0, 0, 0, "s"},
+	}
+	for _, tt := range tests {
+		hash := Geohash(tt.lat, tt.long, tt.precision)
+		if hash != tt.expected {
+			t.Errorf("Expected %v, got: %v", tt.expected, hash)
+		}
+	}
+}
+
+func TestUpdateURLGeoPoint(t *testing.T) {
+	apiUrl, _ := url.Parse(DiscoveryApiUrl)
+	u, _ := QueryParams{GeoPoint: "dr5ru62bn"}.UpdateURL(*apiUrl, "12345")
+	if u.Query().Get("geoPoint") != "dr5ru62bn" {
+		t.Errorf(
+			"Expected %v, got: %v",
+			"dr5ru62bn",
+			u.Query().Get("geoPoint"),
+		)
+	}
+}
+
+func TestUpdateURLSource(t *testing.T) {
+	apiUrl, _ := url.Parse(DiscoveryApiUrl)
+	u, _ := QueryParams{Source: "ticketmaster"}.UpdateURL(*apiUrl, "12345")
+	if u.Query().Get("source") != "ticketmaster" {
+		t.Errorf(
+			"Expected %v, got: %v",
+			"ticketmaster",
+			u.Query().Get("source"),
+		)
+	}
+}
+
+func TestValidateCountryCode(t *testing.T) {
+	valid := []string{"", CountryUS, CountryGB, "ND"}
+	for _, code := range valid {
+		if err := (QueryParams{CountryCode: code}).Validate(); err != nil {
+			t.Errorf("%q: Unexpected error: %v", code, err)
+		}
+	}
+	invalid := []string{"UK", "us", "XX"}
+	for _, code := range invalid {
+		if err := (QueryParams{CountryCode: code}).Validate(); err == nil {
+			t.Errorf("%q: Expected an error", code)
+		}
+	}
+	err := (QueryParams{CountryCode: "UK"}).Validate()
+	if err == nil || !strings.Contains(err.Error(), `"GB"`) {
+		t.Errorf("Expected a suggestion of GB, got: %v", err)
+	}
+}
+
+func TestFutureOnly(t *testing.T) {
+	before := time.Now().UTC().Truncate(time.Second)
+	original := QueryParams{Keyword: "radiohead", VenueID: []string{"a"}}
+	q := original.FutureOnly()
+	start, err := time.Parse(DateTimeLayout, q.StartDateTime)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if start.Before(before) || start.After(time.Now()) {
+		t.Errorf("Expected the current time, got: %v", q.StartDateTime)
+	}
+	if original.StartDateTime != "" {
+		t.Errorf("Expected the original to be unchanged, got: %v", original)
+	}
+	if err := q.Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	explicit := QueryParams{StartDateTime: "2016-01-01T00:00:00Z"}.FutureOnly()
+	if explicit.StartDateTime != "2016-01-01T00:00:00Z" {
+		t.Errorf(
+			"Expected %v, got: %v",
+			"2016-01-01T00:00:00Z",
+			explicit.StartDateTime,
+		)
+	}
+}
+
+func TestSetDateRange(t *testing.T) {
+	est := time.FixedZone("EST", -5*60*60)
+	start := time.Date(2024, 1, 2, 10, 4, 5, 999, est)
+	end := start.Add(48 * time.Hour)
+	var q QueryParams
+	q.SetDateRange(start, end)
+	if q.StartDateTime != "2024-01-02T15:04:05Z" {
+		t.Errorf(
+			"Expected %v, got: %v",
+			"2024-01-02T15:04:05Z",
+			q.StartDateTime,
+		)
+	}
+	if q.EndDateTime != "2024-01-04T15:04:05Z" {
+		t.Errorf("Expected %v, got: %v", "2024-01-04T15:04:05Z", q.EndDateTime)
+	}
+	if err := q.Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	q.SetDateRange(start, time.Time{})
+	if q.EndDateTime != "" {
+		t.Errorf("Expected no endDateTime, got: %v", q.EndDateTime)
+	}
+}
+
+func TestValidateDateRange(t *testing.T) {
+	// Other formats are left for the API to check
+	valid := []QueryParams{
+		{StartDateTime: "2024-01-02"},
+		{EndDateTime: "2024-01-02T15:04:05-05:00"},
+		{StartDateTime: "2024-01-04", EndDateTime: "2024-01-02T15:04:05Z"},
+		{
+			StartDateTime: "2024-01-02T15:04:05Z",
+			EndDateTime:   "2024-01-04T15:04:05Z",
+		},
+	}
+	for _, q := range valid {
+		if err := q.Validate(); err != nil {
+			t.Errorf("%+v: Unexpected error: %v", q, err)
+		}
+	}
+	invalid := []QueryParams{
+		{
+			StartDateTime: "2024-01-04T15:04:05Z",
+			EndDateTime:   "2024-01-02T15:04:05Z",
+		},
+		{
+			StartDateTime: "2024-01-02T15:04:05Z",
+			EndDateTime:   "2024-01-02T15:04:05+05:00",
+		},
+	}
+	for _, q := range invalid {
+		if err := q.Validate(); err == nil {
+			t.Errorf("%+v: Expected an error", q)
+		}
+	}
+}
+
+func TestParseDateTime(t *testing.T) {
+	parsed, err := ParseDateTime("2016-07-27T23:30:00Z")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := time.Date(2016, 7, 27, 23, 30, 0, 0, time.UTC)
+	if !parsed.Equal(expected) {
+		t.Errorf("Expected %v, got: %v", expected, parsed)
+	}
+}
+
+func TestLookupDma(t *testing.T) {
+	dma, ok := LookupDma("new york")
+	if !ok || dma.ID != DmaNewYork {
+		t.Errorf("Expected %v, got: %+v", DmaNewYork, dma)
+	}
+	cities := map[string]int{
+		"Boston":        DmaBoston,
+		"san francisco": DmaSanFrancisco,
+		"oakland":       DmaSanFrancisco,
+		"Philadelphia":  DmaPhiladelphia,
+		"fort worth":    DmaDallas,
+		"Washington":    DmaWashington,
+	}
+	for city, expected := range cities {
+		if dma, ok := LookupDma(city); !ok || dma.ID != expected {
+			t.Errorf("%s: Expected %v, got: %+v", city, expected, dma)
+		}
+	}
+	if _, ok := LookupDma("Atlantis"); ok {
+		t.Errorf("Expected no DMA for Atlantis")
+	}
+	if _, ok := LookupDma(""); ok {
+		t.Errorf("Expected no DMA for an empty name")
+	}
+	list := DmaList()
+	list[0].Name = "modified"
+	if DmaList()[0].Name == "modified" {
+		t.Errorf("Expected DmaList to return a copy")
+	}
+}
+
+func TestValidateSort(t *testing.T) {
+	valid := []string{"", SortDateAsc, SortRelevanceDesc, SortRandom}
+	for _, sort := range valid {
+		if err := (QueryParams{Sort: sort}).Validate(); err != nil {
+			t.Errorf("%q: Unexpected error: %v", sort, err)
+		}
+	}
+	invalid := []string{"date", "date asc", "DATE,ASC", "distance,desc"}
+	for _, sort := range invalid {
+		if err := (QueryParams{Sort: sort}).Validate(); err == nil {
+			t.Errorf("%q: Expected an error", sort)
+		}
+	}
+}
+
+func TestEscapeKeyword(t *testing.T) {
+	tests := map[string]string{
+		"radiohead":            "radiohead",
+		"Simon & Garfunkel":    `Simon \& Garfunkel`,
+		"Blink-182":            `Blink\-182`,
+		"Earth, Wind + Fire":   `Earth, Wind \+ Fire`,
+		`AC/DC "live" (tour)?`: `AC\/DC \"live\" \(tour\)\?`,
+	}
+	for keyword, expected := range tests {
+		if escaped := EscapeKeyword(keyword); escaped != expected {
+			t.Errorf("Expected %v, got: %v", expected, escaped)
+		}
+	}
+}
+
+func TestUpdateURLPreferredCountry(t *testing.T) {
+	apiUrl, _ := url.Parse(DiscoveryApiUrl)
+	u, _ := QueryParams{PreferredCountry: "ca"}.UpdateURL(*apiUrl, "12345")
+	if u.Query().Get("preferredCountry") != "ca" {
+		t.Errorf(
+			"Expected %v, got: %v",
+			"ca",
+			u.Query().Get("preferredCountry"),
+		)
+	}
+}
+
+// allQueryParams has every field of QueryParams set
+var allQueryParams = QueryParams{
+	Id:                     "G5diZfkn0B-bh",
+	Sort:                   SortDateAsc,
+	Page:                   2,
+	Size:                   50,
+	Locale:                 "en-us",
+	Keyword:                "a, b",
+	IncludeTest:            "no",
+	IncludeTBA:             "yes",
+	IncludeTBD:             "only",
+	IncludeSpellcheck:      "yes",
+	IncludeFamily:          "only",
+	IncludeLicensedContent: "true",
+	VenueID:                []string{"KovZpZA7AAEA", "KovZpZAEdFtJ"},
+	StartDateTime:          "2024-01-01T00:00:00Z",
+	EndDateTime:            "2024-02-01T00:00:00Z",
+	CountryCode:            "US",
+	StateCode:              "NY",
+	PostalCode:             "10001",
+	AttractionID:           []string{"K8vZ9171ob7"},
+	SegmentID:              []string{"KZFzniwnSyZfZ7v7nJ"},
+	SegmentName:            "Music",
+	GenreID:                []string{"KnvZfZ7vAeA"},
+	SubGenreID:             []string{"KZazBEonSMnZfZ7v6F1", "KZazBEonSMnZfZ7vF17"},
+	ClassificationID:       []string{"KnvZfZ7vAeA", "KnvZfZ7vAvF"},
+	ClassificationName:     "rock",
+	MarketID:               []string{"35", "51"},
+	PromoterID:             "494",
+	DmaID:                  "345",
+	LatLong:                "40.7,-74.0",
+	GeoPoint:               "dr5ru",
+	Radius:                 "25",
+	Unit:                   "miles",
+	Source:                 "ticketmaster",
+	Resource:               []string{ResourceEvents, ResourceVenues},
+	PreferredCountry:       "us",
+}
+
+func TestValues(t *testing.T) {
+	expected := url.Values{
+		"id":                     {"G5diZfkn0B-bh"},
+		"sort":                   {"date,asc"},
+		"page":                   {"2"},
+		"size":                   {"50"},
+		"locale":                 {"en-us"},
+		"keyword":                {"a, b"},
+		"includeTest":            {"no"},
+		"includeTBA":             {"yes"},
+		"includeTBD":             {"only"},
+		"includeSpellcheck":      {"yes"},
+		"includeFamily":          {"only"},
+		"includeLicensedContent": {"true"},
+		"venueId":                {"KovZpZA7AAEA", "KovZpZAEdFtJ"},
+		"startDateTime":          {"2024-01-01T00:00:00Z"},
+		"endDateTime":            {"2024-02-01T00:00:00Z"},
+		"countryCode":            {"US"},
+		"stateCode":              {"NY"},
+		"postalCode":             {"10001"},
+		"attractionId":           {"K8vZ9171ob7"},
+		"segmentId":              {"KZFzniwnSyZfZ7v7nJ"},
+		"segmentName":            {"Music"},
+		"genreId":                {"KnvZfZ7vAeA"},
+		"subGenreId":             {"KZazBEonSMnZfZ7v6F1", "KZazBEonSMnZfZ7vF17"},
+		"classificationId":       {"KnvZfZ7vAeA", "KnvZfZ7vAvF"},
+		"classificationName":     {"rock"},
+		"marketId":               {"35", "51"},
+		"promoterId":             {"494"},
+		"dmaId":                  {"345"},
+		"latlong":                {"40.7,-74.0"},
+		"geoPoint":               {"dr5ru"},
+		"radius":                 {"25"},
+		"unit":                   {"miles"},
+		"source":                 {"ticketmaster"},
+		"resource":               {"events", "venues"},
+		"preferredCountry":       {"us"},
+	}
+	values := allQueryParams.Values()
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got: %v", expected, values)
+	}
+}
+
+// TestValuesCoversAllFields guards against fields being added to
+// QueryParams without being added to Values
+func TestValuesCoversAllFields(t *testing.T) {
+	values := allQueryParams.Values()
+	v := reflect.ValueOf(allQueryParams)
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.Name == "Extra" {
+			// Covered by TestValuesExtra
+			continue
+		}
+		if v.Field(i).IsZero() {
+			t.Errorf("allQueryParams is missing a value for %s", field.Name)
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if !values.Has(name) {
+			t.Errorf("Expected %v in values, got: %v", name, values)
+		}
+	}
+}
+
+func TestValuesExtra(t *testing.T) {
+	q := QueryParams{
+		Keyword: "radiohead",
+		Extra: url.Values{
+			"keyword":  {"ignored"},
+			"apikey":   {"ignored"},
+			"domain":   {"canada"},
+			"sourceId": {"a", "b"},
+		},
+	}
+	values := q.Values()
+	expected := url.Values{
+		"keyword":  {"radiohead"},
+		"domain":   {"canada"},
+		"sourceId": {"a", "b"},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got: %v", expected, values)
+	}
+
+	apiUrl, _ := url.Parse(DiscoveryApiUrl)
+	u, err := q.UpdateURL(*apiUrl, "12345")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if apikey := u.Query()["apikey"]; !reflect.DeepEqual(apikey, []string{"12345"}) {
+		t.Errorf("Expected %v, got: %v", []string{"12345"}, apikey)
+	}
+	if domain := u.Query().Get("domain"); domain != "canada" {
+		t.Errorf("Expected %v, got: %v", "canada", domain)
+	}
+}
+
+func TestValuesEmpty(t *testing.T) {
+	if values := (QueryParams{}).Values(); len(values) != 0 {
+		t.Errorf("Expected no values, got: %v", values)
+	}
+}
+
+func TestUpdateURLAllFields(t *testing.T) {
+	apiUrl, _ := url.Parse(DiscoveryApiUrl)
+	u, err := allQueryParams.UpdateURL(*apiUrl, "12345")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := allQueryParams.Values()
+	expected.Set("apikey", "12345")
+	if q := u.Query(); !reflect.DeepEqual(q, expected) {
+		t.Errorf("Expected %v, got: %v", expected, q)
+	}
+}
+
+func TestUpdateURLEmptyApiKey(t *testing.T) {
+	apiUrl, _ := url.Parse(DiscoveryApiUrl)
+	u, err := QueryParams{Keyword: "foo"}.UpdateURL(*apiUrl, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if u.Query().Has("apikey") {
+		t.Errorf("Expected no apikey param, got: %v", u.RawQuery)
+	}
+}
+
+func TestUpdateURLMarketID(t *testing.T) {
+	apiUrl, _ := url.Parse(DiscoveryApiUrl)
+	u, _ := QueryParams{MarketID: []string{"1", "2"}}.UpdateURL(*apiUrl, "")
+	if u.RawQuery != "marketId=1&marketId=2" {
+		t.Errorf("Expected %v, got: %v", "marketId=1&marketId=2", u.RawQuery)
+	}
+}
+
+func TestQueryParamsBuilder(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	params, err := NewQueryParamsBuilder().
+		Keyword("radiohead").
+		CountryCode(CountryUS).
+		Size(50).
+		Sort(SortDateAsc).
+		DateRange(start, time.Time{}).
+		VenueID("a").
+		VenueID("b", "c").
+		DmaID(DmaNewYork).
+		PostalCode("10001").
+		Radius(25, "miles").
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := QueryParams{
+		Keyword:       "radiohead",
+		CountryCode:   "US",
+		Size:          50,
+		Sort:          "date,asc",
+		StartDateTime: "2024-01-01T00:00:00Z",
+		VenueID:       []string{"a", "b", "c"},
+		DmaID:         "345",
+		PostalCode:    "10001",
+		Radius:        "25",
+		Unit:          "miles",
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("Expected %+v, got: %+v", expected, params)
+	}
+}
+
+func TestQueryParamsBuilderValidates(t *testing.T) {
+	_, err := NewQueryParamsBuilder().Size(500).Build()
+	if err == nil {
+		t.Errorf("Expected an error for size 500")
+	}
+	_, err = NewQueryParamsBuilder().Sort("bogus").Build()
+	if err == nil {
+		t.Errorf("Expected an error for an unknown sort")
+	}
+}
+
+func TestFormatLatLong(t *testing.T) {
+	tests := map[[2]float64]string{
+		{40.7497062, -73.9916006}: "40.7497062,-73.9916006",
+		{51.5, 0}:                 "51.5,0",
+		{-33.8688, 151.2093}:      "-33.8688,151.2093",
+	}
+	for coords, expected := range tests {
+		if latlong := FormatLatLong(coords[0], coords[1]); latlong != expected {
+			t.Errorf("Expected %v, got: %v", expected, latlong)
+		}
+	}
+}
+
+func TestValidateUnit(t *testing.T) {
+	for _, unit := range []string{"", UnitMiles, UnitKm} {
+		if err := (QueryParams{Unit: unit}).Validate(); err != nil {
+			t.Errorf("%q: Unexpected error: %v", unit, err)
+		}
+	}
+	for _, unit := range []string{"mi", "kilometers", "Miles"} {
+		if err := (QueryParams{Unit: unit}).Validate(); err == nil {
+			t.Errorf("%q: Expected an error", unit)
+		}
+	}
+}
+
+func TestValidateIncludeFilters(t *testing.T) {
+	valid := []QueryParams{
+		{},
+		{IncludeTest: IncludeOnly, IncludeTBA: IncludeYes, IncludeTBD: IncludeNo},
+		{IncludeSpellcheck: IncludeYes, IncludeFamily: IncludeOnly},
+	}
+	for _, q := range valid {
+		if err := q.Validate(); err != nil {
+			t.Errorf("%+v: Unexpected error: %v", q, err)
+		}
+	}
+	invalid := []QueryParams{
+		{IncludeTest: "true"},
+		{IncludeTBA: "Yes"},
+		{IncludeTBD: "1"},
+		{IncludeSpellcheck: IncludeOnly},
+		{IncludeFamily: "false"},
+	}
+	for _, q := range invalid {
+		if err := q.Validate(); err == nil {
+			t.Errorf("%+v: Expected an error", q)
+		}
+	}
+}
+
+func TestValuesDefaultUnit(t *testing.T) {
+	values := QueryParams{Radius: "10"}.Values()
+	if values.Get("unit") != DefaultUnit {
+		t.Errorf("Expected %v, got: %v", DefaultUnit, values.Get("unit"))
+	}
+	values = QueryParams{Radius: "10", Unit: UnitKm}.Values()
+	if values.Get("unit") != UnitKm {
+		t.Errorf("Expected %v, got: %v", UnitKm, values.Get("unit"))
+	}
+	if (QueryParams{}).Values().Has("unit") {
+		t.Errorf("Expected no unit without a radius")
+	}
+}
+
+func TestClone(t *testing.T) {
+	original := allQueryParams.Clone()
+	clone := original.Clone()
+	if !reflect.DeepEqual(clone, original) {
+		t.Fatalf("Expected %+v, got: %+v", original, clone)
+	}
+	// Modify every slice of the clone, which shouldn't affect the original
+	v := reflect.ValueOf(&clone).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).Kind() == reflect.Slice {
+			v.Field(i).Index(0).SetString("changed")
+		}
+	}
+	if !reflect.DeepEqual(original, allQueryParams) {
+		t.Errorf("Expected %+v, got: %+v", allQueryParams, original)
+	}
+}
+
+func TestCloneExtra(t *testing.T) {
+	original := QueryParams{Extra: url.Values{"domain": {"canada"}}}
+	clone := original.Clone()
+	clone.Extra["domain"][0] = "changed"
+	clone.Extra.Set("sourceId", "a")
+	expected := url.Values{"domain": {"canada"}}
+	if !reflect.DeepEqual(original.Extra, expected) {
+		t.Errorf("Expected %v, got: %v", expected, original.Extra)
+	}
+}
+
+func TestWithPage(t *testing.T) {
+	original := QueryParams{Page: 1, VenueID: []string{"a"}}
+	next := original.WithPage(2)
+	next.VenueID[0] = "b"
+	if next.Page != 2 {
+		t.Errorf("Expected %v, got: %v", 2, next.Page)
+	}
+	if original.Page != 1 || original.VenueID[0] != "a" {
+		t.Errorf("Expected original to be unchanged, got: %+v", original)
+	}
+}
+
+func TestUpdateURLIncludeFamily(t *testing.T) {
+	apiUrl, _ := url.Parse(DiscoveryApiUrl)
+	u, _ := QueryParams{IncludeFamily: "only"}.UpdateURL(*apiUrl, "12345")
+	if u.Query().Get("includeFamily") != "only" {
+		t.Errorf(
+			"Expected %v, got: %v",
+			"only",
+			u.Query().Get("includeFamily"),
+		)
+	}
+}
+
+func TestIncludeLicensedContent(t *testing.T) {
+	params := QueryParams{IncludeLicensedContent: "false"}
+	if got := params.Values().Get("includeLicensedContent"); got != "false" {
+		t.Errorf("Expected %v, got: %v", "false", got)
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(data) != `{"includeLicensedContent":"false"}` {
+		t.Errorf("Expected %v, got: %s", `{"includeLicensedContent":"false"}`, data)
+	}
+}
+
+func TestQueryParamsFromValues(t *testing.T) {
+	params := QueryParamsFromValues(allQueryParams.Values())
+	if !reflect.DeepEqual(params, allQueryParams) {
+		t.Errorf("Expected %+v, got: %+v", allQueryParams, params)
+	}
+}
+
+func TestQueryParamsFromValuesIgnoresUnknown(t *testing.T) {
+	values := url.Values{
+		"keyword":  {"radiohead"},
+		"size":     {"ten"},
+		"page":     {"2"},
+		"callback": {"jsonp"},
+	}
+	params := QueryParamsFromValues(values)
+	expected := QueryParams{Keyword: "radiohead", Page: 2}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("Expected %+v, got: %+v", expected, params)
+	}
+}
+
+func TestEncodeParseQueryParams(t *testing.T) {
+	encoded := allQueryParams.Encode()
+	if strings.Contains(encoded, "apikey") {
+		t.Errorf("Expected no API key, got: %v", encoded)
+	}
+	params, err := ParseQueryParams(encoded)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(params, allQueryParams) {
+		t.Errorf("Expected %+v, got: %+v", allQueryParams, params)
+	}
+
+	if _, err := ParseQueryParams("keyword=%zz"); err == nil {
+		t.Errorf("Expected error for invalid query")
+	}
+}
+
+func TestParseQueryParamsExtra(t *testing.T) {
+	params, err := ParseQueryParams("keyword=radiohead&domain=canada&apikey=12345")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := QueryParams{
+		Keyword: "radiohead",
+		Extra:   url.Values{"domain": {"canada"}},
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("Expected %+v, got: %+v", expected, params)
+	}
+}
+
+func TestSetRadius(t *testing.T) {
+	var q QueryParams
+	q.SetRadius(10, "")
+	if q.Radius != "10" || q.Unit != DefaultUnit {
+		t.Errorf("Expected 10 %v, got: %v %v", DefaultUnit, q.Radius, q.Unit)
+	}
+	err := q.Validate()
+	if err == nil {
+		t.Fatalf("Expected an error for a radius without a location")
+	}
+	for _, name := range []string{"latlong", "postalCode", "geoPoint"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("Expected %v in error, got: %v", name, err)
+		}
+	}
+	located := []QueryParams{
+		{Radius: "10", LatLong: "40.7,-73.9"},
+		{Radius: "10", PostalCode: "10001"},
+		{Radius: "10", GeoPoint: "dr5regw3p"},
+	}
+	for _, q := range located {
+		if err := q.Validate(); err != nil {
+			t.Errorf("%+v: Unexpected error: %v", q, err)
+		}
+	}
+}
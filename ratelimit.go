@@ -0,0 +1,55 @@
+package discoverygo
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit is the API quota reported by the Rate-Limit response headers
+type RateLimit struct {
+	// Total number of calls allowed in the current window
+	Limit int
+	// Number of calls remaining in the current window
+	Available int
+	// When the current window resets, in milliseconds since the epoch
+	Reset int
+}
+
+// ResetTime returns Reset as a time.Time
+func (r RateLimit) ResetTime() time.Time {
+	return time.UnixMilli(int64(r.Reset))
+}
+
+// parseRateLimit reads the rate limit headers from a response. The second
+// return value is false if the response didn't include them
+func parseRateLimit(header http.Header) (RateLimit, bool) {
+	limit, err := strconv.Atoi(header.Get("Rate-Limit"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+	available, _ := strconv.Atoi(header.Get("Rate-Limit-Available"))
+	reset, _ := strconv.Atoi(header.Get("Rate-Limit-Reset"))
+	return RateLimit{Limit: limit, Available: available, Reset: reset}, true
+}
+
+// LastRateLimit returns the rate limit reported by the most recent
+// response that included rate limit headers. The second return value is
+// false if no such response has been received yet
+func (d *DiscoveryClient) LastRateLimit() (RateLimit, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.rateLimit, d.hasRateLimit
+}
+
+// recordRateLimit stores the rate limit from the given response headers
+func (d *DiscoveryClient) recordRateLimit(header http.Header) {
+	rateLimit, ok := parseRateLimit(header)
+	if !ok {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.rateLimit = rateLimit
+	d.hasRateLimit = true
+}
@@ -0,0 +1,58 @@
+package discoverygo
+
+import (
+	"errors"
+	"net/http"
+)
+
+// maxRedirects is the number of redirects followed before giving up, the
+// same as http.Client's default policy
+const maxRedirects = 10
+
+// errTooManyRedirects is returned when a request is redirected more than
+// maxRedirects times
+var errTooManyRedirects = errors.New("Stopped after 10 redirects")
+
+// checkRedirect returns a redirect policy for http.Client.CheckRedirect.
+// Redirects aren't followed if DisableRedirects is set. Otherwise, for
+// redirects to the same host the API key is re-added according to the
+// client's AuthMode, since the Location header may not include it. For
+// redirects to other hosts, or to another scheme (e.g. https to http), the
+// API key is removed, so the key isn't leaked. If next is given, it's
+// called after the policy is applied
+func (d *DiscoveryClient) checkRedirect(
+	next func(req *http.Request, via []*http.Request) error,
+) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if d.DisableRedirects {
+			return http.ErrUseLastResponse
+		}
+		if next == nil && len(via) >= maxRedirects {
+			return errTooManyRedirects
+		}
+		apiKey := d.requestAPIKey(req.Context())
+		if req.URL.Host != via[0].URL.Host ||
+			req.URL.Scheme != via[0].URL.Scheme {
+			req.Header.Del(ApiKeyHeader)
+			if q := req.URL.Query(); apiKey != "" && q.Get("apikey") == apiKey {
+				q.Del("apikey")
+				req.URL.RawQuery = q.Encode()
+			}
+		} else if apiKey != "" {
+			switch d.AuthMode {
+			case AuthHeader:
+				req.Header.Set(ApiKeyHeader, apiKey)
+			default:
+				q := req.URL.Query()
+				if q.Get("apikey") == "" {
+					q.Set("apikey", apiKey)
+					req.URL.RawQuery = q.Encode()
+				}
+			}
+		}
+		if next != nil {
+			return next(req, via)
+		}
+		return nil
+	}
+}
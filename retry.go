@@ -0,0 +1,48 @@
+package discoverygo
+
+import (
+	"net/http"
+	"slices"
+	"time"
+)
+
+// RetryPolicy controls which responses are retried, up to the client's
+// MaxRetries, and how long to wait before each retry. A Retry-After header
+// on the response always takes precedence over Backoff
+type RetryPolicy struct {
+	// Status codes that are retried. If empty, only 429 Too Many Requests
+	// is retried
+	RetryableStatuses []int
+	// Returns the delay before the given retry, counting from zero. If nil,
+	// the delay starts at one second and doubles with each retry, up to a
+	// minute
+	Backoff func(attempt int) time.Duration
+}
+
+// retryable returns true if a response with the given status code should
+// be retried
+func (p *RetryPolicy) retryable(status int) bool {
+	if p == nil || len(p.RetryableStatuses) == 0 {
+		return status == http.StatusTooManyRequests
+	}
+	return slices.Contains(p.RetryableStatuses, status)
+}
+
+// backoff returns the delay before the given retry, used when the response
+// has no Retry-After header
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	if p == nil || p.Backoff == nil {
+		return defaultBackoff(attempt)
+	}
+	return p.Backoff(attempt)
+}
+
+// defaultBackoff returns retryBackoff doubled for each attempt, capped at
+// maxRetryBackoff so large attempts can't overflow
+func defaultBackoff(attempt int) time.Duration {
+	delay := retryBackoff
+	for i := 0; i < attempt && delay > 0 && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryBackoff)
+}
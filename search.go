@@ -0,0 +1,79 @@
+package discoverygo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// SearchResults are the results of searching events, attractions and venues
+// at once with Search. Each search succeeds or fails on its own, so a
+// result is nil if its error is set
+type SearchResults struct {
+	Events         *PagedResponse
+	EventsErr      error
+	Attractions    *PagedResponse
+	AttractionsErr error
+	Venues         *PagedResponse
+	VenuesErr      error
+}
+
+// Err returns an error joining the errors of each failed search, or nil if
+// they all succeeded
+func (r *SearchResults) Err() error {
+	var errs []error
+	if r.EventsErr != nil {
+		errs = append(errs, fmt.Errorf("events: %w", r.EventsErr))
+	}
+	if r.AttractionsErr != nil {
+		errs = append(errs, fmt.Errorf("attractions: %w", r.AttractionsErr))
+	}
+	if r.VenuesErr != nil {
+		errs = append(errs, fmt.Errorf("venues: %w", r.VenuesErr))
+	}
+	return errors.Join(errs...)
+}
+
+// Search searches events, attractions and venues for the given keyword at
+// once. The keyword overrides queryParams.Keyword. Unlike Suggest, each
+// resource is searched in full, so results can be paged through
+func (d *DiscoveryClient) Search(
+	keyword string,
+	queryParams QueryParams,
+) (*SearchResults, error) {
+	return d.SearchContext(context.Background(), keyword, queryParams)
+}
+
+// SearchContext searches events, attractions and venues for the given
+// keyword, sending the three requests concurrently with the given context.
+// An error is only returned if the query parameters are invalid. Failed
+// searches are reported by the errors on SearchResults
+func (d *DiscoveryClient) SearchContext(
+	ctx context.Context,
+	keyword string,
+	queryParams QueryParams,
+) (*SearchResults, error) {
+	queryParams.Keyword = keyword
+	if err := queryParams.Validate(); err != nil {
+		return nil, err
+	}
+	var rs SearchResults
+	var wg sync.WaitGroup
+	search := func(
+		fn func(context.Context, QueryParams) (*PagedResponse, error),
+		out **PagedResponse,
+		outErr *error,
+	) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			*out, *outErr = fn(ctx, queryParams.Clone())
+		}()
+	}
+	search(d.SearchEventsContext, &rs.Events, &rs.EventsErr)
+	search(d.SearchAttractionsContext, &rs.Attractions, &rs.AttractionsErr)
+	search(d.SearchVenuesContext, &rs.Venues, &rs.VenuesErr)
+	wg.Wait()
+	return &rs, nil
+}
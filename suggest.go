@@ -0,0 +1,41 @@
+package discoverygo
+
+import "context"
+
+// Suggest resources, for QueryParams.Resource
+const (
+	ResourceAttractions = "attractions"
+	ResourceEvents      = "events"
+	ResourceVenues      = "venues"
+	ResourceProducts    = "products"
+)
+
+// Suggest returns attractions, events and venues matching the given
+// keyword, for autocomplete. The keyword overrides queryParams.Keyword,
+// and queryParams.Resource limits which resource types are returned
+// See: https://developer.ticketmaster.com/products-and-docs/apis/discovery-api/v2/#suggest-v2
+func (d *DiscoveryClient) Suggest(
+	keyword string,
+	queryParams QueryParams,
+) (*SuggestResponse, error) {
+	return d.SuggestContext(context.Background(), keyword, queryParams)
+}
+
+// SuggestContext returns attractions, events and venues matching the given
+// keyword, for autocomplete. The request is bound to the given context
+func (d *DiscoveryClient) SuggestContext(
+	ctx context.Context,
+	keyword string,
+	queryParams QueryParams,
+) (*SuggestResponse, error) {
+	queryParams.Keyword = keyword
+	suggestUrl, err := d.searchURL(d.SuggestUrl(), queryParams)
+	if err != nil {
+		return nil, err
+	}
+	var rs SuggestResponse
+	if err := d.doRequest(ctx, *suggestUrl, &rs); err != nil {
+		return nil, err
+	}
+	return &rs, nil
+}
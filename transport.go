@@ -0,0 +1,31 @@
+package discoverygo
+
+import (
+	"net/http"
+)
+
+// DefaultMaxIdleConnsPerHost is the number of idle connections to the API
+// kept open by the transport NewTransport returns. http.DefaultTransport
+// only keeps two, so concurrent requests would otherwise keep opening new
+// connections
+const DefaultMaxIdleConnsPerHost = 16
+
+// defaultTransport is shared by clients created with NewDiscoveryClient
+// without WithHTTPClient or WithTransport, so they reuse connections
+var defaultTransport = NewTransport()
+
+// NewTransport returns a copy of http.DefaultTransport, with keep-alives
+// enabled and up to DefaultMaxIdleConnsPerHost idle connections kept per
+// host. It can be tuned further and given to WithTransport
+func NewTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	return transport
+}
+
+// CloseIdleConnections closes any idle connections held by the client's
+// transport. Connections are otherwise reused between requests, as long as
+// response bodies (from Do) are read to the end and closed
+func (d *DiscoveryClient) CloseIdleConnections() {
+	d.httpClient().CloseIdleConnections()
+}
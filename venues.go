@@ -0,0 +1,79 @@
+package discoverygo
+
+import (
+	"context"
+	"net/url"
+)
+
+// GetVenue returns a venue by its ID
+// See: https://developer.ticketmaster.com/products-and-docs/apis/discovery-api/v2/#venue-details-v2
+func (d *DiscoveryClient) GetVenue(id string) (*Venue, error) {
+	return d.GetVenueContext(context.Background(), id)
+}
+
+// GetVenueContext returns a venue by its ID. The request is bound to the
+// given context
+func (d *DiscoveryClient) GetVenueContext(
+	ctx context.Context,
+	id string,
+) (*Venue, error) {
+	var rs Venue
+	if err := d.doRequest(ctx, d.venueUrl(id), &rs); err != nil {
+		return nil, err
+	}
+	return &rs, nil
+}
+
+// SearchVenues returns a list of venues matching the given query parameters
+// See: https://developer.ticketmaster.com/products-and-docs/apis/discovery-api/v2/#search-venues-v2
+func (d *DiscoveryClient) SearchVenues(
+	queryParams QueryParams,
+) (*PagedResponse, error) {
+	return d.SearchVenuesContext(context.Background(), queryParams)
+}
+
+// SearchVenuesContext returns a list of venues matching the given query
+// parameters. The request is bound to the given context
+func (d *DiscoveryClient) SearchVenuesContext(
+	ctx context.Context,
+	queryParams QueryParams,
+) (*PagedResponse, error) {
+	venuesUrl, err := d.searchURL(d.VenuesUrl(), queryParams)
+	if err != nil {
+		return nil, err
+	}
+	var rs PagedResponse
+	if err := d.doRequest(ctx, *venuesUrl, &rs); err != nil {
+		return nil, err
+	}
+	rs.params = &queryParams
+	return &rs, nil
+}
+
+// EventsForVenue returns the upcoming events at the venue with the given
+// ID, e.g. for a venue's calendar. The ID overrides queryParams.VenueID.
+// Unless set in queryParams, events are sorted by date and start from now
+func (d *DiscoveryClient) EventsForVenue(
+	venueID string,
+	queryParams QueryParams,
+) (*PagedResponse, error) {
+	return d.EventsForVenueContext(context.Background(), venueID, queryParams)
+}
+
+// EventsForVenueContext returns the upcoming events at the venue with the
+// given ID. The request is bound to the given context
+func (d *DiscoveryClient) EventsForVenueContext(
+	ctx context.Context,
+	venueID string,
+	queryParams QueryParams,
+) (*PagedResponse, error) {
+	queryParams = queryParams.upcoming()
+	queryParams.VenueID = []string{venueID}
+	return d.SearchEventsContext(ctx, queryParams)
+}
+
+// venueUrl returns the URL to the venue with the given ID
+func (d *DiscoveryClient) venueUrl(id string) url.URL {
+	baseVenueUrl := d.VenuesUrl()
+	return *baseVenueUrl.JoinPath(id)
+}